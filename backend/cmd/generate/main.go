@@ -2,15 +2,21 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
+	_ "time/tzdata" // STRAVA_TIMEZONE must resolve without system zone data
 
+	"github.com/mrcodeeu/homepage/internal/assets"
 	"github.com/mrcodeeu/homepage/internal/config"
 	"github.com/mrcodeeu/homepage/internal/models"
 	"github.com/mrcodeeu/homepage/internal/scrapers"
@@ -24,12 +30,22 @@ const (
 )
 
 var (
-	outputDir = flag.String("output", dataDir, "Output directory for generated data files")
-	cachePath = flag.String("cache", cacheDir, "Cache directory for cookies and temporary data")
-	sources   = flag.String("sources", "all", "Data sources to generate (all, github, strava, linkedin)")
-	verbose   = flag.Bool("verbose", false, "Enable verbose logging")
+	outputDir    = flag.String("output", dataDir, "Output directory for generated data files")
+	cachePath    = flag.String("cache", cacheDir, "Cache directory for cookies and temporary data")
+	sources      = flag.String("sources", "all", "Data sources to generate (all, github, activity, strava, linkedin)")
+	verbose      = flag.Bool("verbose", false, "Enable verbose logging")
+	mirrorImages = flag.Bool("mirror-images", true, "Download project images next to the generated data instead of hotlinking them")
+	assetsURL    = flag.String("assets-url", "/assets", "URL prefix under which mirrored images are served")
+	imageWidths  = flag.String("image-widths", "320,640,1280", "Comma-separated widths of the resized image variants (empty to disable)")
 )
 
+// assetOptions configures how project images are mirrored
+type assetOptions struct {
+	mirror bool   // download images instead of hotlinking them
+	url    string // URL prefix of the mirrored files
+	widths []int  // widths of the resized variants
+}
+
 func main() {
 	flag.Parse()
 
@@ -69,6 +85,7 @@ func main() {
 	generateAll := *sources == "all"
 	shouldGenerate := map[string]bool{
 		"github":   generateAll,
+		"activity": generateAll,
 		"strava":   generateAll,
 		"linkedin": generateAll,
 	}
@@ -78,17 +95,22 @@ func main() {
 			if _, known := shouldGenerate[s]; known {
 				shouldGenerate[s] = true
 			} else if s != "" {
-				log.Printf("Warning: unknown source %q (valid: github, strava, linkedin, all)", s)
+				log.Printf("Warning: unknown source %q (valid: github, activity, strava, linkedin, all)", s)
 			}
 		}
 	}
 
+	// Stop scraping cleanly on Ctrl+C or when the CI job is cancelled
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Track errors
 	hasErrors := false
 
 	// Generate GitHub data
 	if shouldGenerate["github"] {
-		if err := generateGitHub(cfg, cache, *outputDir); err != nil {
+		options := assetOptions{mirror: *mirrorImages, url: *assetsURL, widths: parseWidths(*imageWidths)}
+		if err := generateGitHub(ctx, cfg, cache, *outputDir, options); err != nil {
 			log.Printf("Error generating GitHub data: %v", err)
 			hasErrors = true
 		} else if *verbose {
@@ -96,9 +118,19 @@ func main() {
 		}
 	}
 
+	// Generate GitHub contribution activity
+	if shouldGenerate["activity"] {
+		if err := generateGitHubActivity(ctx, cfg, cache, *outputDir); err != nil {
+			log.Printf("Error generating GitHub activity data: %v", err)
+			hasErrors = true
+		} else if *verbose {
+			log.Println("✓ GitHub activity data generated successfully")
+		}
+	}
+
 	// Generate Strava data
 	if shouldGenerate["strava"] {
-		if err := generateStrava(cfg, cache, *outputDir); err != nil {
+		if err := generateStrava(cfg, cache, *outputDir, persistentCacheDir); err != nil {
 			log.Printf("Error generating Strava data: %v", err)
 			hasErrors = true
 		} else if *verbose {
@@ -123,11 +155,12 @@ func main() {
 	// Exit with error code if any generation failed
 	if hasErrors {
 		log.Println("Data generation completed with errors")
+		stop()
 		os.Exit(1)
 	}
 }
 
-func generateGitHub(cfg *config.Config, cache storage.Cache, outputDir string) error {
+func generateGitHub(ctx context.Context, cfg *config.Config, cache storage.Cache, outputDir string, options assetOptions) error {
 	log.Println("Generating GitHub data...")
 
 	if cfg.GitHubUsername == "" {
@@ -145,19 +178,148 @@ func generateGitHub(cfg *config.Config, cache storage.Cache, outputDir string) e
 	log.Printf("GitHub token present: %v", cfg.GitHubToken != "")
 
 	scraper := scrapers.NewGitHubScraper(cfg.GitHubUsername, cfg.GitHubToken, cache)
-	data, err := scraper.Scrape()
+	if err := scraper.SetMode(cfg.GitHubMode); err != nil {
+		return err
+	}
+
+	sources := make([]scrapers.GitHubSource, 0, len(cfg.GitHubSources))
+	for _, entry := range cfg.GitHubSources {
+		source, err := scrapers.ParseGitHubSource(entry)
+		if err != nil {
+			log.Printf("Warning: ignoring GitHub source: %v", err)
+			continue
+		}
+		sources = append(sources, source)
+	}
+	if len(sources) > 0 {
+		log.Printf("Additional GitHub sources: %v", sources)
+	}
+	scraper.SetSources(sources)
+
+	if err := scraper.SetInclusionRules(scrapers.InclusionRules{
+		IncludeForks:     cfg.GitHubIncludeForks,
+		IncludeArchived:  cfg.GitHubIncludeArchived,
+		IncludeTemplates: cfg.GitHubIncludeTemplates,
+		AllowTopics:      cfg.GitHubTopicsAllow,
+		DenyTopics:       cfg.GitHubTopicsDeny,
+		IncludeNames:     cfg.GitHubRepoInclude,
+		ExcludeNames:     cfg.GitHubRepoExclude,
+	}); err != nil {
+		return err
+	}
+	scraper.SetLegacyEmojiMarker(cfg.GitHubLegacyEmoji)
+	scraper.SetConcurrency(cfg.GitHubWorkers)
+	scraper.SetReadmeExcerpt(cfg.GitHubReadmeExcerpt)
+
+	data, err := scraper.ScrapeContext(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to scrape: %w", err)
 	}
 
+	logDecisions(scraper.Decisions())
+
+	if portfolioErrors := scraper.PortfolioErrors(); len(portfolioErrors) > 0 {
+		log.Printf("WARNING: %d repositories have invalid portfolio metadata and were skipped:", len(portfolioErrors))
+		for _, portfolioErr := range portfolioErrors {
+			log.Printf("  %s (%s):", portfolioErr.Repo, portfolioErr.File)
+			for _, issue := range portfolioErr.Issues {
+				log.Printf("    - %s: %s", issue.Field, issue.Message)
+			}
+		}
+	}
+
 	if err := validateGitHubData(data); err != nil {
 		return fmt.Errorf("GitHub data validation failed: %w", err)
 	}
+	projects := data.([]scrapers.Project)
+
+	if options.mirror {
+		if err := mirrorProjectImages(projects, cache, filepath.Join(outputDir, "assets"), options); err != nil {
+			return err
+		}
+	}
 
-	return saveJSON(filepath.Join(outputDir, "github.json"), "github", data)
+	if err := saveJSON(filepath.Join(outputDir, "github.json"), "github", projects); err != nil {
+		return err
+	}
+
+	// Grouped view by category, kept in a separate file so github.json
+	// stays a flat list for existing consumers
+	groups := scrapers.GroupProjects(projects)
+	return saveJSON(filepath.Join(outputDir, "github_groups.json"), "github", groups)
 }
 
-func generateStrava(cfg *config.Config, cache storage.Cache, outputDir string) error {
+func generateGitHubActivity(ctx context.Context, cfg *config.Config, cache storage.Cache, outputDir string) error {
+	log.Println("Generating GitHub activity data...")
+
+	if cfg.GitHubUsername == "" {
+		return fmt.Errorf("GITHUB_USERNAME not set")
+	}
+
+	scraper := scrapers.NewGitHubActivityScraper(cfg.GitHubUsername, cfg.GitHubToken, cache)
+	scraper.SetMaxEvents(cfg.GitHubActivityEvents)
+
+	data, err := scraper.ScrapeContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to scrape: %w", err)
+	}
+
+	if err := validateGitHubActivity(data); err != nil {
+		return fmt.Errorf("GitHub activity validation failed: %w", err)
+	}
+
+	return saveJSON(filepath.Join(outputDir, "github_activity.json"), "github_activity", data)
+}
+
+// logDecisions prints why each repository was published or skipped
+func logDecisions(decisions []scrapers.RepoDecision) {
+	included := 0
+	log.Println("Repository decisions:")
+	for _, decision := range decisions {
+		status := "skip"
+		if decision.Included {
+			status = "keep"
+			included++
+		}
+		log.Printf("  [%s] %s: %s", status, decision.Repo, decision.Reason)
+	}
+	log.Printf("%d of %d repositories published", included, len(decisions))
+}
+
+// mirrorProjectImages downloads project images into assetsDir, generates
+// their resized variants and rewrites the image URLs to the local copies.
+// Images that cannot be downloaded keep their remote URL. Assets no longer
+// referenced by any project are removed.
+func mirrorProjectImages(projects []scrapers.Project, cache storage.Cache, assetsDir string, options assetOptions) error {
+	mirror := assets.NewMirror(assetsDir, options.url, cache)
+	mirror.SetVariantWidths(options.widths)
+
+	mirrored, failed := 0, 0
+	for i := range projects {
+		project := &projects[i]
+		project.ImageAssets = make([]models.ImageAsset, 0, len(project.Images))
+		for j, image := range project.Images {
+			asset, err := mirror.Mirror(image)
+			if err != nil {
+				log.Printf("Warning: keeping remote image for %s: %s: %v", project.Name, image, err)
+				failed++
+				continue
+			}
+			project.Images[j] = asset.URL
+			project.ImageAssets = append(project.ImageAssets, asset)
+			mirrored++
+		}
+	}
+
+	removed, err := mirror.CollectGarbage()
+	if err != nil {
+		return fmt.Errorf("failed to clean up assets: %w", err)
+	}
+	log.Printf("Mirrored %d project images (%d failed, %d unused assets removed)", mirrored, failed, removed)
+	return nil
+}
+
+func generateStrava(cfg *config.Config, cache storage.Cache, outputDir, cacheDir string) error {
 	log.Println("Generating Strava data...")
 
 	if cfg.StravaClientID == "" || cfg.StravaClientSecret == "" || cfg.StravaRefreshToken == "" {
@@ -172,6 +334,53 @@ func generateStrava(cfg *config.Config, cache storage.Cache, outputDir string) e
 		cfg.StravaRefreshToken,
 		cache,
 	)
+	scraper.SetFullSync(cfg.StravaFullSync)
+	scraper.SetDetailRequestLimit(cfg.StravaDetailRequests)
+	scraper.SetTrendMonths(cfg.StravaTrendMonths)
+	if err := scraper.SetTimezone(cfg.StravaTimezone); err != nil {
+		return fmt.Errorf("invalid STRAVA_TIMEZONE: %w", err)
+	}
+	zones, err := scrapers.ParsePrivacyZones(cfg.StravaPrivacyZones)
+	if err != nil {
+		return fmt.Errorf("invalid STRAVA_PRIVACY_ZONES: %w", err)
+	}
+	scraper.SetPrivacyZones(zones)
+	scraper.SetRouteOptions(cfg.StravaRoutePoints, cfg.StravaRouteLimit, cfg.StravaHeatmapCell)
+	if err := scraper.SetPrivacyRules(scrapers.PrivacyRules{
+		IncludePrivate:  cfg.StravaIncludePrivate,
+		Visibilities:    cfg.StravaVisibility,
+		IncludeCommutes: cfg.StravaIncludeCommutes,
+		IncludeHidden:   cfg.StravaIncludeHidden,
+		RedactPatterns:  cfg.StravaRedactPatterns,
+		RoundStartTime:  time.Duration(cfg.StravaRoundStartMins) * time.Minute,
+		DropHeartrate:   cfg.StravaDropHeartrate,
+	}); err != nil {
+		return fmt.Errorf("invalid STRAVA_REDACT_PATTERNS: %w", err)
+	}
+	if cfg.StravaDisciplinesFile != "" {
+		disciplines, err := scrapers.LoadDisciplines(cfg.StravaDisciplinesFile)
+		if err != nil {
+			return err
+		}
+		if err := scraper.SetDisciplines(disciplines); err != nil {
+			return err
+		}
+		log.Printf("Strava disciplines: %d from %s", len(disciplines), cfg.StravaDisciplinesFile)
+	}
+	if len(cfg.StravaRecordDistances) > 0 {
+		if err := scraper.SetRecordDistances(cfg.StravaRecordDistances); err != nil {
+			return fmt.Errorf("invalid STRAVA_PR_DISTANCES: %w", err)
+		}
+	}
+
+	// Strava rotates refresh tokens; keep the latest one for the next run
+	tokenFile := cfg.StravaTokenFile
+	if tokenFile == "" {
+		tokenFile = filepath.Join(cacheDir, "strava_token.json")
+	}
+	scraper.SetTokenStore(storage.NewFileTokenStore(tokenFile, cfg.StravaTokenKey))
+	log.Printf("Strava token store: %s (encrypted: %v)", tokenFile, cfg.StravaTokenKey != "")
+
 	data, err := scraper.Scrape()
 	if err != nil {
 		return fmt.Errorf("failed to scrape: %w", err)
@@ -210,6 +419,24 @@ func generateLinkedIn(cfg *config.Config, cache storage.Cache, outputDir string)
 	return saveJSON(filepath.Join(outputDir, "linkedin.json"), "linkedin", data)
 }
 
+// parseWidths parses a comma-separated list of positive image widths
+func parseWidths(value string) []int {
+	widths := make([]int, 0)
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		width, err := strconv.Atoi(field)
+		if err != nil || width <= 0 {
+			log.Printf("Warning: ignoring invalid image width %q", field)
+			continue
+		}
+		widths = append(widths, width)
+	}
+	return widths
+}
+
 func validateGitHubData(data any) error {
 	projects, ok := data.([]scrapers.Project)
 	if !ok {
@@ -221,6 +448,20 @@ func validateGitHubData(data any) error {
 	return nil
 }
 
+func validateGitHubActivity(data any) error {
+	activity, ok := data.(*scrapers.GitHubActivity)
+	if !ok {
+		return fmt.Errorf("unexpected data type: %T", data)
+	}
+	if activity == nil {
+		return fmt.Errorf("GitHub activity is nil")
+	}
+	if activity.Calendar == nil && len(activity.Events) == 0 {
+		return fmt.Errorf("no contributions or events found")
+	}
+	return nil
+}
+
 func validateStravaData(data any) error {
 	// Handle both value and pointer types
 	var stravaData models.StravaData
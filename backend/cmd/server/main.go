@@ -68,8 +68,13 @@ func main() {
 	mux.HandleFunc("/api/health", handleHealth)
 	mux.HandleFunc("/api/cv", handleCV)
 	mux.HandleFunc("/api/projects", handleProjects)
+	mux.HandleFunc("/api/projects/groups", handleProjectGroups)
+	mux.HandleFunc("/api/activity", handleActivity)
 	mux.HandleFunc("/api/strava", handleStrava)
 
+	// Mirrored project images (content-addressed, see cmd/generate)
+	mux.Handle("/assets/", assetHandler(dataLoader.AssetsDir()))
+
 	// Create server
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%s", cfg.Port),
@@ -107,6 +112,18 @@ func main() {
 	log.Println("Server stopped")
 }
 
+// assetHandler serves mirrored project images. They come from third-party
+// repositories, so an SVG opened directly must not run scripts on this
+// origin; the headers match the Caddyfile.
+func assetHandler(dir string) http.Handler {
+	files := http.StripPrefix("/assets/", http.FileServer(http.Dir(dir)))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		files.ServeHTTP(w, r)
+	})
+}
+
 // Middleware for CORS
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -201,6 +218,62 @@ func handleProjects(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Project groups endpoint - loads GitHub projects grouped by category
+func handleProjectGroups(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Groups are written by the next data generation run
+	if !dataLoader.DataExists("github_groups") {
+		log.Printf("GitHub project groups file not found - data generation may not have run since groups were added")
+		if err := json.NewEncoder(w).Encode([]interface{}{}); err != nil {
+			log.Printf("Error encoding empty groups response: %v", err)
+		}
+		return
+	}
+
+	groups, err := dataLoader.LoadGitHubGroups()
+	if err != nil {
+		log.Printf("Error loading GitHub project groups: %v", err)
+		http.Error(w, "Failed to load project groups", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(groups); err != nil {
+		http.Error(w, "Failed to encode project groups", http.StatusInternalServerError)
+		log.Printf("Error encoding project groups response: %v", err)
+	}
+}
+
+// Activity endpoint - loads the GitHub contribution calendar and recent events
+func handleActivity(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// The activity source is newer than the others; older data sets lack it
+	if !dataLoader.DataExists("github_activity") {
+		log.Printf("GitHub activity data file not found - data generation may not have run for the activity source")
+		emptyData := map[string]interface{}{
+			"events":       []interface{}{},
+			"repo_commits": []interface{}{},
+		}
+		if err := json.NewEncoder(w).Encode(emptyData); err != nil {
+			log.Printf("Error encoding empty activity data: %v", err)
+		}
+		return
+	}
+
+	activity, err := dataLoader.LoadGitHubActivity()
+	if err != nil {
+		log.Printf("Error loading GitHub activity data: %v", err)
+		http.Error(w, "Failed to load activity", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(activity); err != nil {
+		http.Error(w, "Failed to encode activity data", http.StatusInternalServerError)
+		log.Printf("Error encoding activity response: %v", err)
+	}
+}
+
 // Strava endpoint - loads Strava data
 func handleStrava(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -42,6 +42,19 @@ func setupTestData(t *testing.T) (cleanup func()) {
 		},
 	}
 
+	// Create test GitHub project groups
+	githubGroups := models.GeneratedData{
+		GeneratedAt: time.Now(),
+		Source:      "github",
+		Version:     "1.0",
+		Data: []map[string]interface{}{
+			{
+				"category": "Tools",
+				"projects": githubData.Data,
+			},
+		},
+	}
+
 	// Create test LinkedIn data
 	linkedInData := models.GeneratedData{
 		GeneratedAt: time.Now(),
@@ -119,6 +132,7 @@ func setupTestData(t *testing.T) (cleanup func()) {
 
 	// Write test files
 	writeTestJSON(t, tempDir, "github.json", githubData)
+	writeTestJSON(t, tempDir, "github_groups.json", githubGroups)
 	writeTestJSON(t, tempDir, "linkedin.json", linkedInData)
 	writeTestJSON(t, tempDir, "strava.json", stravaData)
 
@@ -242,6 +256,98 @@ func TestHandleProjects(t *testing.T) {
 	}
 }
 
+func TestHandleProjectGroups(t *testing.T) {
+	cleanup := setupTestData(t)
+	defer cleanup()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/projects/groups", nil)
+	w := httptest.NewRecorder()
+
+	handleProjectGroups(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	var response []struct {
+		Category string                   `json:"category"`
+		Projects []map[string]interface{} `json:"projects"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(response) != 1 || response[0].Category != "Tools" {
+		t.Fatalf("Expected one Tools group, got %+v", response)
+	}
+	if len(response[0].Projects) != 1 || response[0].Projects[0]["name"] != "test-project" {
+		t.Errorf("Expected test-project in Tools group, got %+v", response[0].Projects)
+	}
+}
+
+func TestHandleProjectGroupsMissing(t *testing.T) {
+	dataLoader = storage.NewDataLoader(t.TempDir())
+
+	w := httptest.NewRecorder()
+	handleProjectGroups(w, httptest.NewRequest(http.MethodGet, "/api/projects/groups", nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != "[]\n" {
+		t.Errorf("Expected an empty list before groups are generated, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleActivity(t *testing.T) {
+	dir := t.TempDir()
+	dataLoader = storage.NewDataLoader(dir)
+
+	// Without a generated file the endpoint answers with empty lists
+	w := httptest.NewRecorder()
+	handleActivity(w, httptest.NewRequest(http.MethodGet, "/api/activity", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 without activity data, got %d", w.Code)
+	}
+	var empty map[string][]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&empty); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if events, ok := empty["events"]; !ok || len(events) != 0 {
+		t.Errorf("Expected an empty event list, got %v", empty)
+	}
+
+	writeTestJSON(t, dir, "github_activity.json", models.GeneratedData{
+		GeneratedAt: time.Now(),
+		Source:      "github_activity",
+		Version:     "1.0",
+		Data: map[string]interface{}{
+			"username": "testuser",
+			"calendar": map[string]interface{}{
+				"total": 3,
+				"days":  []map[string]interface{}{{"date": "2026-01-01", "count": 3, "level": 4}},
+			},
+			"events":       []map[string]interface{}{{"type": "push", "repo": "testuser/tool"}},
+			"repo_commits": []map[string]interface{}{{"repo": "testuser/tool", "commits": 3}},
+		},
+	})
+
+	w = httptest.NewRecorder()
+	handleActivity(w, httptest.NewRequest(http.MethodGet, "/api/activity", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var response struct {
+		Calendar struct {
+			Total int `json:"total"`
+		} `json:"calendar"`
+		Events []map[string]interface{} `json:"events"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Calendar.Total != 3 || len(response.Events) != 1 {
+		t.Errorf("Unexpected activity response: %+v", response)
+	}
+}
+
 func TestHandleStrava(t *testing.T) {
 	cleanup := setupTestData(t)
 	defer cleanup()
@@ -322,3 +428,30 @@ func TestCORSPreflight(t *testing.T) {
 		t.Errorf("Expected status 200 for OPTIONS request, got %d", w.Code)
 	}
 }
+
+func TestAssetHandler(t *testing.T) {
+	dir := t.TempDir()
+	svg := `<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`
+	if err := os.WriteFile(filepath.Join(dir, "logo.svg"), []byte(svg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	handler := assetHandler(dir)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets/logo.svg", nil))
+	if w.Code != http.StatusOK || w.Body.String() != svg {
+		t.Fatalf("Expected the SVG to be served, got %d", w.Code)
+	}
+	if csp := w.Header().Get("Content-Security-Policy"); csp != "default-src 'none'; style-src 'unsafe-inline'; sandbox" {
+		t.Errorf("Expected a sandboxing CSP, got %q", csp)
+	}
+	if w.Header().Get("X-Content-Type-Options") != "nosniff" {
+		t.Error("Expected nosniff")
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets/missing.png", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing asset, got %d", w.Code)
+	}
+}
@@ -3,9 +3,11 @@ module github.com/mrcodeeu/homepage
 go 1.24
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/chromedp/cdproto v0.0.0-20250803210736-d308e07a266d
 	github.com/chromedp/chromedp v0.14.2
 	github.com/pquerna/otp v1.5.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
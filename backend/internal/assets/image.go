@@ -0,0 +1,126 @@
+package assets
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"image"
+	_ "image/gif"  // register GIF decoder for image.DecodeConfig
+	_ "image/jpeg" // register JPEG decoder for image.DecodeConfig
+	_ "image/png"  // register PNG decoder for image.DecodeConfig
+	"math"
+	"strconv"
+	"strings"
+)
+
+// dimensions returns the pixel size of an image, or zeros when the format
+// does not carry one (e.g. an SVG with neither width/height nor viewBox)
+func dimensions(data []byte, mime string) (int, int) {
+	switch mime {
+	case "image/svg+xml":
+		return svgDimensions(data)
+	case "image/webp":
+		return webpDimensions(data)
+	default:
+		config, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return 0, 0
+		}
+		return config.Width, config.Height
+	}
+}
+
+// isSVG reports whether data is SVG markup, i.e. XML whose root element is <svg>
+func isSVG(data []byte) bool {
+	_, ok := svgRoot(data)
+	return ok
+}
+
+// svgRoot returns the root element of an SVG document
+func svgRoot(data []byte) (xml.StartElement, bool) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return xml.StartElement{}, false
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start, strings.EqualFold(start.Name.Local, "svg")
+		}
+	}
+}
+
+// svgDimensions reads the size from the width/height attributes of the root
+// element, falling back to the viewBox. Relative units yield zeros.
+func svgDimensions(data []byte) (int, int) {
+	root, ok := svgRoot(data)
+	if !ok {
+		return 0, 0
+	}
+
+	var width, height, viewBox string
+	for _, attr := range root.Attr {
+		switch attr.Name.Local {
+		case "width":
+			width = attr.Value
+		case "height":
+			height = attr.Value
+		case "viewBox":
+			viewBox = attr.Value
+		}
+	}
+
+	w, wOK := svgLength(width)
+	h, hOK := svgLength(height)
+	if wOK && hOK {
+		return w, h
+	}
+
+	fields := strings.Fields(strings.ReplaceAll(viewBox, ",", " "))
+	if len(fields) == 4 {
+		vw, errW := strconv.ParseFloat(fields[2], 64)
+		vh, errH := strconv.ParseFloat(fields[3], 64)
+		if errW == nil && errH == nil && vw > 0 && vh > 0 {
+			return int(math.Round(vw)), int(math.Round(vh))
+		}
+	}
+	return 0, 0
+}
+
+// svgLength parses an absolute SVG length in pixels ("120" or "120px")
+func svgLength(value string) (int, bool) {
+	value = strings.TrimSuffix(strings.TrimSpace(value), "px")
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return int(math.Round(n)), true
+}
+
+// webpDimensions reads the canvas size from a WebP header (lossy VP8,
+// lossless VP8L or extended VP8X)
+func webpDimensions(data []byte) (int, int) {
+	if len(data) < 30 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return 0, 0
+	}
+
+	chunk := data[12:]
+	switch string(chunk[0:4]) {
+	case "VP8 ":
+		// Frame header: 3 bytes frame tag, 3 bytes start code, then 14-bit sizes
+		w := int(binary.LittleEndian.Uint16(chunk[14:16]) & 0x3fff)
+		h := int(binary.LittleEndian.Uint16(chunk[16:18]) & 0x3fff)
+		return w, h
+	case "VP8L":
+		// Signature byte, then 14-bit width-1 and height-1
+		bits := binary.LittleEndian.Uint32(chunk[9:13])
+		return int(bits&0x3fff) + 1, int((bits>>14)&0x3fff) + 1
+	case "VP8X":
+		// 24-bit canvas width-1 and height-1 after flags and reserved bytes
+		w := int(chunk[12]) | int(chunk[13])<<8 | int(chunk[14])<<16
+		h := int(chunk[15]) | int(chunk[16])<<8 | int(chunk[17])<<16
+		return w + 1, h + 1
+	}
+	return 0, 0
+}
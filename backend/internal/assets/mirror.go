@@ -0,0 +1,354 @@
+package assets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mrcodeeu/homepage/internal/models"
+	"github.com/mrcodeeu/homepage/internal/storage"
+)
+
+const (
+	// maxImageSize caps a single download, matching the LinkedIn image limit
+	maxImageSize = 10 * 1024 * 1024 // 10MB
+
+	// cacheKeyAssetPrefix prefixes the validators stored per source URL
+	cacheKeyAssetPrefix = "asset_"
+	assetCacheTTL       = 30 * 24 * time.Hour
+
+	// hashPrefixLength is the number of hex digits of the content hash used
+	// in file names; the full hash is recorded in the asset metadata
+	hashPrefixLength = 16
+)
+
+// extensions maps supported image MIME types to file extensions
+var extensions = map[string]string{
+	"image/png":     ".png",
+	"image/jpeg":    ".jpg",
+	"image/gif":     ".gif",
+	"image/webp":    ".webp",
+	"image/svg+xml": ".svg",
+	"image/avif":    ".avif",
+}
+
+// cacheEntry remembers how a source URL was mirrored, so unchanged images
+// are revalidated with a conditional request instead of downloaded again
+type cacheEntry struct {
+	ETag         string            `json:"etag,omitempty"`
+	LastModified string            `json:"last_modified,omitempty"`
+	Asset        models.ImageAsset `json:"asset"`
+}
+
+// Mirror downloads remote images into a content-addressed directory and
+// describes the local copies. Files are named after their content hash, so
+// the same image referenced by several projects is stored once.
+type Mirror struct {
+	dir       string
+	urlPrefix string
+	cache     storage.Cache
+	client    *http.Client
+
+	// variantWidths are the widths of the resized copies of raster images
+	variantWidths []int
+
+	mu   sync.Mutex
+	used map[string]bool // file names referenced since the mirror was created
+}
+
+// NewMirror creates a mirror writing to dir. Local copies are referenced as
+// urlPrefix + "/" + file name; cache stores the HTTP validators per image
+// and may be nil.
+func NewMirror(dir, urlPrefix string, cache storage.Cache) *Mirror {
+	return &Mirror{
+		dir:       dir,
+		urlPrefix: strings.TrimSuffix(urlPrefix, "/"),
+		cache:     cache,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		variantWidths: DefaultVariantWidths,
+		used:          make(map[string]bool),
+	}
+}
+
+// SetVariantWidths sets the widths of the resized copies generated for
+// raster images; an empty list disables variants
+func (m *Mirror) SetVariantWidths(widths []int) {
+	sorted := slices.Clone(widths)
+	slices.Sort(sorted)
+	m.variantWidths = slices.Compact(sorted)
+}
+
+// Mirror stores a local copy of the image at sourceURL and returns its
+// metadata. When the image cannot be downloaded but a previous copy still
+// exists, that copy is used.
+func (m *Mirror) Mirror(sourceURL string) (models.ImageAsset, error) {
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return models.ImageAsset{}, fmt.Errorf("failed to create asset directory: %w", err)
+	}
+
+	key := cacheKey(sourceURL)
+	entry := m.loadEntry(key)
+
+	result, err := m.fetch(sourceURL, entry)
+	if err != nil {
+		if entry == nil {
+			return models.ImageAsset{}, err
+		}
+		log.Printf("Warning: failed to refresh %s, keeping previous copy: %v", sourceURL, err)
+		result = *entry
+	}
+	asset := m.localize(result.Asset)
+
+	if !m.derived(asset) {
+		if err := m.derive(&asset); err != nil {
+			log.Printf("Warning: failed to generate variants of %s: %v", sourceURL, err)
+		}
+	}
+	result.Asset = asset
+	m.storeEntry(key, result)
+
+	m.mu.Lock()
+	m.used[path.Base(asset.URL)] = true
+	for _, variant := range asset.Variants {
+		m.used[path.Base(variant.URL)] = true
+	}
+	m.mu.Unlock()
+	return asset, nil
+}
+
+// localize points the URLs of an asset at the current URL prefix, since
+// cached entries may predate a change of the prefix
+func (m *Mirror) localize(asset models.ImageAsset) models.ImageAsset {
+	asset.URL = m.urlPrefix + "/" + path.Base(asset.URL)
+	variants := make([]models.ImageVariant, len(asset.Variants))
+	for i, variant := range asset.Variants {
+		variant.URL = m.urlPrefix + "/" + path.Base(variant.URL)
+		variants[i] = variant
+	}
+	asset.Variants = variants
+	return asset
+}
+
+// fetch downloads sourceURL unless the cached copy is still current
+func (m *Mirror) fetch(sourceURL string, entry *cacheEntry) (cacheEntry, error) {
+	req, err := http.NewRequest(http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return cacheEntry{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	if entry != nil {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return cacheEntry{}, fmt.Errorf("failed to download: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Printf("Warning: failed to close response body: %v", closeErr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		return *entry, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return cacheEntry{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageSize+1))
+	if err != nil {
+		return cacheEntry{}, fmt.Errorf("failed to read image data: %w", err)
+	}
+	if len(data) > maxImageSize {
+		return cacheEntry{}, fmt.Errorf("image exceeds %d bytes", maxImageSize)
+	}
+
+	asset, err := m.store(sourceURL, data, resp.Header.Get("Content-Type"))
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	if entry != nil && entry.Asset.Hash == asset.Hash {
+		// Unchanged content, e.g. refetched for lack of validators: keep
+		// the derived data instead of generating it again
+		asset.Variants = entry.Asset.Variants
+		asset.Placeholder = entry.Asset.Placeholder
+		asset.DominantColor = entry.Asset.DominantColor
+	}
+
+	return cacheEntry{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Asset:        asset,
+	}, nil
+}
+
+// store writes image data under its content hash and describes it
+func (m *Mirror) store(sourceURL string, data []byte, contentType string) (models.ImageAsset, error) {
+	mime := detectMIME(data, contentType)
+	ext, ok := extensions[mime]
+	if !ok {
+		return models.ImageAsset{}, fmt.Errorf("unsupported content type %q", mime)
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	name := hash[:hashPrefixLength] + ext
+
+	target := filepath.Join(m.dir, name)
+	if _, err := os.Stat(target); os.IsNotExist(err) {
+		if err := writeFileAtomic(target, data); err != nil {
+			return models.ImageAsset{}, err
+		}
+	}
+
+	width, height := dimensions(data, mime)
+	return models.ImageAsset{
+		URL:    m.urlPrefix + "/" + name,
+		Source: sourceURL,
+		Width:  width,
+		Height: height,
+		MIME:   mime,
+		Hash:   hash,
+		Size:   int64(len(data)),
+	}, nil
+}
+
+// CollectGarbage removes files from the asset directory that were not
+// referenced through Mirror since the mirror was created. Hidden files such
+// as .gitkeep are left alone. It returns the number of removed files.
+func (m *Mirror) CollectGarbage() (int, error) {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read asset directory: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || m.used[name] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(m.dir, name)); err != nil {
+			return removed, fmt.Errorf("failed to remove unused asset %s: %w", name, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// loadEntry returns the cached mirror state of a source URL, provided the
+// local file it points to still exists
+func (m *Mirror) loadEntry(key string) *cacheEntry {
+	if m.cache == nil {
+		return nil
+	}
+	data, err := m.cache.Get(key)
+	if err != nil {
+		log.Printf("Warning: failed to read asset cache: %v", err)
+		return nil
+	}
+	if data == nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(m.dir, path.Base(entry.Asset.URL))); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// storeEntry caches the mirror state of a source URL. Responses without
+// validators are cached too: they are downloaded again, but unchanged
+// content keeps its derived variants (see fetch).
+func (m *Mirror) storeEntry(key string, entry cacheEntry) {
+	if m.cache == nil {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("Warning: failed to marshal asset cache entry: %v", err)
+		return
+	}
+	if err := m.cache.Set(key, data, assetCacheTTL); err != nil {
+		log.Printf("Warning: failed to update asset cache: %v", err)
+	}
+}
+
+// cacheKey derives a cache key from a source URL
+func cacheKey(sourceURL string) string {
+	sum := sha256.Sum256([]byte(sourceURL))
+	return cacheKeyAssetPrefix + hex.EncodeToString(sum[:16])
+}
+
+// detectMIME determines the image type from the content. The Content-Type
+// header is only trusted for formats the standard sniffer does not know,
+// since raw.githubusercontent.com serves most files as text/plain.
+func detectMIME(data []byte, contentType string) string {
+	detected := http.DetectContentType(data)
+	if strings.HasPrefix(detected, "image/") {
+		return detected
+	}
+	if isSVG(data) {
+		return "image/svg+xml"
+	}
+
+	header := strings.TrimSpace(strings.ToLower(strings.Split(contentType, ";")[0]))
+	if header == "image/avif" {
+		return header
+	}
+	return strings.Split(detected, ";")[0]
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place
+func writeFileAtomic(target string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create asset file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write asset file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write asset file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write asset file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write asset file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package assets
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mrcodeeu/homepage/internal/storage"
+)
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.RGBA{R: 200, A: 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func newTestMirror(t *testing.T) (*Mirror, string) {
+	t.Helper()
+	cache, err := storage.NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	dir := filepath.Join(t.TempDir(), "assets")
+	return NewMirror(dir, "/assets/", cache), dir
+}
+
+func TestMirror_StoresImages(t *testing.T) {
+	pngData := testPNG(t, 40, 30)
+	svgData := []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 120 60"></svg>`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/screenshot.png", "/copy.png":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = w.Write(pngData)
+		case "/diagram.svg":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = w.Write(svgData)
+		case "/page.png":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("<!DOCTYPE html><html><body>Not found</body></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	mirror, dir := newTestMirror(t)
+
+	asset, err := mirror.Mirror(server.URL + "/screenshot.png")
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+	if asset.MIME != "image/png" || asset.Width != 40 || asset.Height != 30 {
+		t.Errorf("Unexpected PNG metadata: %+v", asset)
+	}
+	if asset.Size != int64(len(pngData)) || len(asset.Hash) != 64 {
+		t.Errorf("Unexpected size/hash: %d %q", asset.Size, asset.Hash)
+	}
+	if asset.Source != server.URL+"/screenshot.png" {
+		t.Errorf("Expected source URL to be recorded, got %q", asset.Source)
+	}
+	if asset.URL != "/assets/"+asset.Hash[:hashPrefixLength]+".png" {
+		t.Errorf("Expected content-addressed URL, got %q", asset.URL)
+	}
+	stored, err := os.ReadFile(filepath.Join(dir, filepath.Base(asset.URL)))
+	if err != nil || !bytes.Equal(stored, pngData) {
+		t.Errorf("Expected image to be written to the asset directory (err: %v)", err)
+	}
+
+	duplicate, err := mirror.Mirror(server.URL + "/copy.png")
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+	if duplicate.URL != asset.URL {
+		t.Errorf("Expected identical content to share a file, got %q and %q", asset.URL, duplicate.URL)
+	}
+
+	svg, err := mirror.Mirror(server.URL + "/diagram.svg")
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+	if svg.MIME != "image/svg+xml" || svg.Width != 120 || svg.Height != 60 || !strings.HasSuffix(svg.URL, ".svg") {
+		t.Errorf("Unexpected SVG metadata: %+v", svg)
+	}
+
+	if _, err := mirror.Mirror(server.URL + "/page.png"); err == nil {
+		t.Error("Expected HTML served as image/png to be rejected")
+	}
+	if _, err := mirror.Mirror(server.URL + "/missing.png"); err == nil {
+		t.Error("Expected missing image to fail")
+	}
+}
+
+func TestMirror_RevalidatesAndFallsBack(t *testing.T) {
+	pngData := testPNG(t, 8, 8)
+	var downloads atomic.Int32
+	var broken atomic.Bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if broken.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write(pngData)
+	}))
+	defer server.Close()
+
+	mirror, _ := newTestMirror(t)
+	first, err := mirror.Mirror(server.URL + "/image.png")
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+
+	second, err := mirror.Mirror(server.URL + "/image.png")
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+	if downloads.Load() != 1 {
+		t.Errorf("Expected unchanged image to be revalidated, got %d downloads", downloads.Load())
+	}
+	if !reflect.DeepEqual(second, first) {
+		t.Errorf("Expected cached asset %+v, got %+v", first, second)
+	}
+
+	broken.Store(true)
+	third, err := mirror.Mirror(server.URL + "/image.png")
+	if err != nil {
+		t.Fatalf("Expected previous copy to be used when the download fails, got %v", err)
+	}
+	if third.URL != first.URL {
+		t.Errorf("Expected previous copy %q, got %q", first.URL, third.URL)
+	}
+}
+
+func TestMirror_CollectGarbage(t *testing.T) {
+	pngData := testPNG(t, 4, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(pngData)
+	}))
+	defer server.Close()
+
+	mirror, dir := newTestMirror(t)
+	asset, err := mirror.Mirror(server.URL + "/keep.png")
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+
+	for _, name := range []string{"0123456789abcdef.png", ".gitkeep"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	removed, err := mirror.CollectGarbage()
+	if err != nil {
+		t.Fatalf("CollectGarbage failed: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Expected 1 removed asset, got %d", removed)
+	}
+
+	for name, want := range map[string]bool{
+		filepath.Base(asset.URL): true,
+		".gitkeep":               true,
+		"0123456789abcdef.png":   false,
+	} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if exists := err == nil; exists != want {
+			t.Errorf("%s: expected exists=%v, got %v", name, want, exists)
+		}
+	}
+}
+
+func TestDimensions(t *testing.T) {
+	// 30x20 canvas in the VP8X header: width-1 and height-1 as 24-bit values
+	vp8x := append([]byte("RIFF\x00\x00\x00\x00WEBPVP8X\x0a\x00\x00\x00\x00\x00\x00\x00"),
+		29, 0, 0, 19, 0, 0, 0, 0, 0, 0, 0, 0)
+
+	tests := []struct {
+		name          string
+		data          []byte
+		mime          string
+		width, height int
+	}{
+		{"PNG", testPNG(t, 12, 7), "image/png", 12, 7},
+		{"SVG attributes", []byte(`<svg width="64px" height="32" viewBox="0 0 10 10"/>`), "image/svg+xml", 64, 32},
+		{"SVG viewBox", []byte(`<svg viewBox="0,0,300.4,150"/>`), "image/svg+xml", 300, 150},
+		{"SVG relative size", []byte(`<svg width="100%" height="100%"/>`), "image/svg+xml", 0, 0},
+		{"WebP VP8X", vp8x, "image/webp", 30, 20},
+		{"truncated WebP", []byte("RIFF"), "image/webp", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height := dimensions(tt.data, tt.mime)
+			if width != tt.width || height != tt.height {
+				t.Errorf("Expected %dx%d, got %dx%d", tt.width, tt.height, width, height)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package assets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mrcodeeu/homepage/internal/models"
+)
+
+// DefaultVariantWidths are the srcset widths generated for raster images
+var DefaultVariantWidths = []int{320, 640, 1280}
+
+const (
+	// placeholderWidth is the width of the blurred loading placeholder
+	placeholderWidth = 16
+	// colorSampleWidth is the width images are reduced to before
+	// looking for the dominant color
+	colorSampleWidth = 64
+	jpegQuality      = 82
+	// maxDerivePixels bounds the images decoded for variants. Images come
+	// from arbitrary repositories, and a small file can declare huge
+	// dimensions; larger images keep only their original.
+	maxDerivePixels = 25_000_000
+)
+
+// resizable reports whether variants can be derived from an image type.
+// SVGs scale by themselves; WebP and AVIF cannot be decoded by the standard
+// library.
+func resizable(mime string) bool {
+	switch mime {
+	case "image/png", "image/jpeg", "image/gif":
+		return true
+	default:
+		return false
+	}
+}
+
+// expectedVariantWidths returns the configured widths narrower than the image
+func (m *Mirror) expectedVariantWidths(imageWidth int) []int {
+	widths := make([]int, 0, len(m.variantWidths))
+	for _, width := range m.variantWidths {
+		if width < imageWidth {
+			widths = append(widths, width)
+		}
+	}
+	return widths
+}
+
+// derived reports whether an asset already carries up-to-date variants,
+// placeholder and dominant color, with all variant files present
+func (m *Mirror) derived(asset models.ImageAsset) bool {
+	if !resizable(asset.MIME) {
+		return true
+	}
+	if asset.Placeholder == "" {
+		return false
+	}
+
+	expected := make([]int, 0)
+	if asset.MIME != "image/gif" {
+		expected = m.expectedVariantWidths(asset.Width)
+	}
+	if len(asset.Variants) != len(expected) {
+		return false
+	}
+	for i, variant := range asset.Variants {
+		if variant.Width != expected[i] {
+			return false
+		}
+		if _, err := os.Stat(filepath.Join(m.dir, path.Base(variant.URL))); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// derive generates the resized variants, the placeholder and the dominant
+// color of a raster asset from its local copy. Animated GIFs would lose
+// their animation, so GIFs only get a placeholder and dominant color.
+func (m *Mirror) derive(asset *models.ImageAsset) error {
+	if !resizable(asset.MIME) {
+		return nil
+	}
+
+	name := path.Base(asset.URL)
+	data, err := os.ReadFile(filepath.Join(m.dir, name))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+	if int64(config.Width)*int64(config.Height) > maxDerivePixels {
+		return fmt.Errorf("image %s is too large (%dx%d)", name, config.Width, config.Height)
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+	src := toRGBA(decoded)
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+	if width == 0 || height == 0 {
+		return fmt.Errorf("image %s is empty", name)
+	}
+
+	variants := make([]models.ImageVariant, 0)
+	if asset.MIME != "image/gif" {
+		base := strings.TrimSuffix(name, path.Ext(name))
+		for _, variantWidth := range m.expectedVariantWidths(width) {
+			variantHeight := scaledHeight(width, height, variantWidth)
+			encoded, ext, err := encodeVariant(resize(src, variantWidth, variantHeight), asset.MIME)
+			if err != nil {
+				return fmt.Errorf("failed to encode %dw variant of %s: %w", variantWidth, name, err)
+			}
+
+			variantName := fmt.Sprintf("%s-%dw%s", base, variantWidth, ext)
+			target := filepath.Join(m.dir, variantName)
+			if _, err := os.Stat(target); os.IsNotExist(err) {
+				if err := writeFileAtomic(target, encoded); err != nil {
+					return err
+				}
+			}
+			variants = append(variants, models.ImageVariant{
+				URL:    m.urlPrefix + "/" + variantName,
+				Width:  variantWidth,
+				Height: variantHeight,
+				Size:   int64(len(encoded)),
+			})
+		}
+	}
+
+	var placeholder bytes.Buffer
+	thumbnail := resize(src, placeholderWidth, scaledHeight(width, height, placeholderWidth))
+	if err := png.Encode(&placeholder, thumbnail); err != nil {
+		return fmt.Errorf("failed to encode placeholder of %s: %w", name, err)
+	}
+
+	sample := src
+	if width > colorSampleWidth {
+		sample = resize(src, colorSampleWidth, scaledHeight(width, height, colorSampleWidth))
+	}
+
+	asset.Variants = variants
+	asset.Placeholder = "data:image/png;base64," + base64.StdEncoding.EncodeToString(placeholder.Bytes())
+	asset.DominantColor = dominantColor(sample)
+	return nil
+}
+
+// scaledHeight keeps the aspect ratio when scaling to targetWidth
+func scaledHeight(width, height, targetWidth int) int {
+	return max(1, (height*targetWidth+width/2)/width)
+}
+
+// encodeVariant encodes a resized image. Opaque images become JPEGs, which
+// are far smaller for screenshots and photos; transparent ones stay PNGs.
+func encodeVariant(img *image.RGBA, mime string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if mime == "image/jpeg" || img.Opaque() {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), ".jpg", nil
+	}
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), ".png", nil
+}
+
+// toRGBA converts any image to RGBA with its origin at (0, 0)
+func toRGBA(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+// resize scales an image by averaging the source pixels covered by each
+// destination pixel (a box filter), which gives smooth results for the
+// downscaling done here
+func resize(src *image.RGBA, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := max(y0+1, (y+1)*srcHeight/height)
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := max(x0+1, (x+1)*srcWidth/width)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				offset := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += int(src.Pix[offset])
+					g += int(src.Pix[offset+1])
+					b += int(src.Pix[offset+2])
+					a += int(src.Pix[offset+3])
+					offset += 4
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// dominantColor returns the most common color of an image as #rrggbb.
+// Colors are bucketed at 4 bits per channel and the winning bucket is
+// averaged; mostly transparent pixels are ignored.
+func dominantColor(img *image.RGBA) string {
+	type bucket struct{ r, g, b, n int }
+	buckets := make(map[int]*bucket)
+
+	for i := 0; i+3 < len(img.Pix); i += 4 {
+		a := int(img.Pix[i+3])
+		if a < 128 {
+			continue
+		}
+		// RGBA is alpha-premultiplied
+		r := int(img.Pix[i]) * 255 / a
+		g := int(img.Pix[i+1]) * 255 / a
+		b := int(img.Pix[i+2]) * 255 / a
+
+		key := (r>>4)<<8 | (g>>4)<<4 | b>>4
+		bk, ok := buckets[key]
+		if !ok {
+			bk = &bucket{}
+			buckets[key] = bk
+		}
+		bk.r += r
+		bk.g += g
+		bk.b += b
+		bk.n++
+	}
+
+	if len(buckets) == 0 {
+		return ""
+	}
+
+	keys := make([]int, 0, len(buckets))
+	for key := range buckets {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	best := keys[0]
+	for _, key := range keys[1:] {
+		if buckets[key].n > buckets[best].n {
+			best = key
+		}
+	}
+
+	bk := buckets[best]
+	return fmt.Sprintf("#%02x%02x%02x", bk.r/bk.n, bk.g/bk.n, bk.b/bk.n)
+}
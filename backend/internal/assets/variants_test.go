@@ -0,0 +1,225 @@
+package assets
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// encodePNG encodes a width x height image filled with fill, with the left
+// quarter painted in accent
+func encodePNG(t *testing.T, width, height int, fill, accent color.NRGBA) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if x < width/4 {
+				img.SetNRGBA(x, y, accent)
+			} else {
+				img.SetNRGBA(x, y, fill)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestMirror_Variants(t *testing.T) {
+	red := color.NRGBA{R: 220, G: 20, B: 20, A: 255}
+	blue := color.NRGBA{B: 255, A: 255}
+	images := map[string][]byte{
+		"/opaque.png":      encodePNG(t, 700, 350, red, blue),
+		"/transparent.png": encodePNG(t, 400, 100, color.NRGBA{G: 200, A: 255}, color.NRGBA{}),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"`+r.URL.Path+`"`)
+		if r.Header.Get("If-None-Match") == `"`+r.URL.Path+`"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write(images[r.URL.Path])
+	}))
+	defer server.Close()
+
+	mirror, dir := newTestMirror(t)
+
+	asset, err := mirror.Mirror(server.URL + "/opaque.png")
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+	if len(asset.Variants) != 2 {
+		t.Fatalf("Expected 320w and 640w variants for a 700px image, got %+v", asset.Variants)
+	}
+	for i, want := range []struct{ width, height int }{{320, 160}, {640, 320}} {
+		variant := asset.Variants[i]
+		if variant.Width != want.width || variant.Height != want.height {
+			t.Errorf("Variant %d: expected %dx%d, got %dx%d", i, want.width, want.height, variant.Width, variant.Height)
+		}
+		if !strings.HasSuffix(variant.URL, ".jpg") {
+			t.Errorf("Expected opaque variant to be a JPEG, got %s", variant.URL)
+		}
+		info, err := os.Stat(filepath.Join(dir, filepath.Base(variant.URL)))
+		if err != nil || info.Size() != variant.Size {
+			t.Errorf("Expected variant file of %d bytes (err: %v)", variant.Size, err)
+		}
+	}
+	if !strings.HasPrefix(asset.Placeholder, "data:image/png;base64,") {
+		t.Errorf("Expected PNG data URI placeholder, got %q", asset.Placeholder)
+	}
+	if asset.DominantColor != "#dc1414" {
+		t.Errorf("Expected dominant color #dc1414, got %q", asset.DominantColor)
+	}
+
+	transparent, err := mirror.Mirror(server.URL + "/transparent.png")
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+	if len(transparent.Variants) != 1 || !strings.HasSuffix(transparent.Variants[0].URL, ".png") {
+		t.Errorf("Expected one PNG variant for a transparent image, got %+v", transparent.Variants)
+	}
+
+	// A deleted variant is regenerated even though the original is unchanged
+	removedVariant := filepath.Join(dir, filepath.Base(asset.Variants[0].URL))
+	if err := os.Remove(removedVariant); err != nil {
+		t.Fatalf("Failed to remove variant: %v", err)
+	}
+	if _, err := mirror.Mirror(server.URL + "/opaque.png"); err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+	if _, err := os.Stat(removedVariant); err != nil {
+		t.Errorf("Expected missing variant to be regenerated: %v", err)
+	}
+
+	removed, err := mirror.CollectGarbage()
+	if err != nil {
+		t.Fatalf("CollectGarbage failed: %v", err)
+	}
+	if removed != 0 {
+		t.Errorf("Expected variants to be kept by garbage collection, %d files removed", removed)
+	}
+}
+
+func TestMirror_VariantsWithoutValidators(t *testing.T) {
+	data := encodePNG(t, 700, 350, color.NRGBA{R: 220, A: 255}, color.NRGBA{B: 255, A: 255})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// No ETag or Last-Modified, so every run downloads the image again
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	mirror, _ := newTestMirror(t)
+	first, err := mirror.Mirror(server.URL + "/shot.png")
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+
+	entry := mirror.loadEntry(cacheKey(server.URL + "/shot.png"))
+	if entry == nil {
+		t.Fatal("Expected the entry to be cached")
+	}
+	refetched, err := mirror.fetch(server.URL+"/shot.png", entry)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if len(refetched.Asset.Variants) != len(first.Variants) || refetched.Asset.Placeholder != first.Placeholder ||
+		refetched.Asset.DominantColor != first.DominantColor {
+		t.Errorf("Expected unchanged content to keep its derived data, got %+v", refetched.Asset)
+	}
+	if !mirror.derived(mirror.localize(refetched.Asset)) {
+		t.Error("Expected no variants to be generated again")
+	}
+}
+
+func TestMirror_VariantsTooLarge(t *testing.T) {
+	// A valid PNG header declaring 100000x100000 pixels with almost no data
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+	huge := buf.Bytes()
+	binary.BigEndian.PutUint32(huge[16:], 100000)
+	binary.BigEndian.PutUint32(huge[20:], 100000)
+	binary.BigEndian.PutUint32(huge[29:], crc32.ChecksumIEEE(huge[12:29]))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(huge)
+	}))
+	defer server.Close()
+
+	mirror, dir := newTestMirror(t)
+	asset, err := mirror.Mirror(server.URL + "/huge.png")
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+	if asset.Width != 100000 || len(asset.Variants) != 0 || asset.Placeholder != "" {
+		t.Errorf("Expected no variants for an oversized image, got %+v", asset)
+	}
+	if _, err := os.Stat(filepath.Join(dir, filepath.Base(asset.URL))); err != nil {
+		t.Errorf("Expected the original to be kept: %v", err)
+	}
+}
+
+func TestMirror_SetVariantWidths(t *testing.T) {
+	pngData := encodePNG(t, 500, 500, color.NRGBA{R: 255, A: 255}, color.NRGBA{R: 255, A: 255})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(pngData)
+	}))
+	defer server.Close()
+
+	mirror, _ := newTestMirror(t)
+	mirror.SetVariantWidths([]int{400, 100, 400, 800})
+
+	asset, err := mirror.Mirror(server.URL + "/image.png")
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+	if len(asset.Variants) != 2 || asset.Variants[0].Width != 100 || asset.Variants[1].Width != 400 {
+		t.Errorf("Expected sorted, deduplicated 100w and 400w variants, got %+v", asset.Variants)
+	}
+}
+
+func TestResize(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			v := uint8(0)
+			if x%2 == 1 {
+				v = 200
+			}
+			src.SetRGBA(x, y, color.RGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+
+	dst := resize(src, 2, 1)
+	for x := 0; x < 2; x++ {
+		if got := dst.RGBAAt(x, 0); got != (color.RGBA{R: 100, G: 100, B: 100, A: 255}) {
+			t.Errorf("Pixel %d: expected averaged gray 100, got %v", x, got)
+		}
+	}
+}
+
+func TestDominantColor(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 1))
+	img.SetRGBA(0, 0, color.RGBA{R: 10, G: 200, B: 10, A: 255})
+	img.SetRGBA(1, 0, color.RGBA{R: 12, G: 202, B: 12, A: 255})
+	img.SetRGBA(2, 0, color.RGBA{R: 250, A: 255})
+
+	if got := dominantColor(img); got != "#0bc90b" {
+		t.Errorf("Expected #0bc90b, got %q", got)
+	}
+	if got := dominantColor(image.NewRGBA(image.Rect(0, 0, 2, 2))); got != "" {
+		t.Errorf("Expected no color for a fully transparent image, got %q", got)
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -12,11 +13,45 @@ type Config struct {
 	// GitHub
 	GitHubToken    string
 	GitHubUsername string
+	GitHubMode     string   // "rest" or "graphql"
+	GitHubSources  []string // extra "user", "org:name" or "owner/repo" entries
+
+	// GitHub inclusion rules, applied before portfolio markers are checked
+	GitHubIncludeForks     bool
+	GitHubIncludeArchived  bool
+	GitHubIncludeTemplates bool
+	GitHubTopicsAllow      []string
+	GitHubTopicsDeny       []string
+	GitHubRepoInclude      []string // name globs, "name" or "owner/name"
+	GitHubRepoExclude      []string
+	GitHubLegacyEmoji      bool // accept a bare 🎨 in READMEs as a marker
+	GitHubWorkers          int  // repositories checked concurrently
+	GitHubReadmeExcerpt    bool // render a sanitized HTML excerpt of each README
+	GitHubActivityEvents   int  // recent events kept in github_activity.json
 
 	// Strava
-	StravaClientID     string
-	StravaClientSecret string
-	StravaRefreshToken string
+	StravaClientID        string
+	StravaClientSecret    string
+	StravaRefreshToken    string
+	StravaFullSync        bool     // refetch the whole history instead of only new activities
+	StravaTokenFile       string   // where rotated refresh tokens are stored; defaults to the cache directory
+	StravaTokenKey        string   // passphrase encrypting the token file; plain text if empty
+	StravaRecordDistances []string // best effort names used for personal records, e.g. "5k"
+	StravaDetailRequests  int      // activity detail calls per run to collect best efforts; 0 disables them
+	StravaTrendMonths     int      // months covered by weekly and monthly trends
+	StravaTimezone        string   // IANA time zone in which trend weeks and months start
+	StravaPrivacyZones    string   // "lat,lng[,radius];..." areas hidden from routes and the heatmap
+	StravaRoutePoints     int      // point budget of each simplified route
+	StravaRouteLimit      int      // most recent activities with a route
+	StravaHeatmapCell     int      // heatmap cell size in meters
+	StravaIncludePrivate  bool     // publish private activities
+	StravaVisibility      []string // published visibilities; "everyone" only if empty
+	StravaIncludeCommutes bool     // publish commutes
+	StravaIncludeHidden   bool     // publish activities hidden from the home feed
+	StravaRedactPatterns  []string // regular expressions for activity names to redact, one per line
+	StravaRoundStartMins  int      // round start times down to this many minutes; 0 keeps them exact
+	StravaDropHeartrate   bool     // remove heart rate data
+	StravaDisciplinesFile string   // YAML, TOML or JSON file defining the discipline taxonomy
 
 	// LinkedIn
 	LinkedInEmail      string
@@ -38,10 +73,43 @@ func Load() *Config {
 
 		GitHubToken:    os.Getenv("GITHUB_TOKEN"),
 		GitHubUsername: getEnv("GITHUB_USERNAME", "mrcodeeu"),
+		GitHubMode:     getEnv("GITHUB_SCRAPER_MODE", "rest"),
+		GitHubSources:  getEnvList("GITHUB_SOURCES"),
+
+		GitHubIncludeForks:     getEnvBool("GITHUB_INCLUDE_FORKS", false),
+		GitHubIncludeArchived:  getEnvBool("GITHUB_INCLUDE_ARCHIVED", false),
+		GitHubIncludeTemplates: getEnvBool("GITHUB_INCLUDE_TEMPLATES", false),
+		GitHubTopicsAllow:      getEnvList("GITHUB_TOPICS_ALLOW"),
+		GitHubTopicsDeny:       getEnvList("GITHUB_TOPICS_DENY"),
+		GitHubRepoInclude:      getEnvList("GITHUB_REPO_INCLUDE"),
+		GitHubRepoExclude:      getEnvList("GITHUB_REPO_EXCLUDE"),
+		GitHubLegacyEmoji:      getEnvBool("GITHUB_LEGACY_EMOJI_MARKER", false),
+		GitHubWorkers:          getEnvInt("GITHUB_WORKERS", 8),
+		GitHubReadmeExcerpt:    getEnvBool("GITHUB_README_EXCERPT", false),
+		GitHubActivityEvents:   getEnvInt("GITHUB_ACTIVITY_EVENTS", 30),
 
-		StravaClientID:     os.Getenv("STRAVA_CLIENT_ID"),
-		StravaClientSecret: os.Getenv("STRAVA_CLIENT_SECRET"),
-		StravaRefreshToken: os.Getenv("STRAVA_REFRESH_TOKEN"),
+		StravaClientID:        os.Getenv("STRAVA_CLIENT_ID"),
+		StravaClientSecret:    os.Getenv("STRAVA_CLIENT_SECRET"),
+		StravaRefreshToken:    os.Getenv("STRAVA_REFRESH_TOKEN"),
+		StravaFullSync:        getEnvBool("STRAVA_FULL_SYNC", false),
+		StravaTokenFile:       os.Getenv("STRAVA_TOKEN_FILE"),
+		StravaTokenKey:        os.Getenv("STRAVA_TOKEN_KEY"),
+		StravaRecordDistances: getEnvList("STRAVA_PR_DISTANCES"),
+		StravaDetailRequests:  getEnvNonNegativeInt("STRAVA_DETAIL_REQUESTS", 50),
+		StravaTrendMonths:     getEnvInt("STRAVA_TREND_MONTHS", 12),
+		StravaTimezone:        getEnv("STRAVA_TIMEZONE", "UTC"),
+		StravaPrivacyZones:    os.Getenv("STRAVA_PRIVACY_ZONES"),
+		StravaRoutePoints:     getEnvInt("STRAVA_ROUTE_POINTS", 100),
+		StravaRouteLimit:      getEnvInt("STRAVA_ROUTE_LIMIT", 50),
+		StravaHeatmapCell:     getEnvInt("STRAVA_HEATMAP_CELL", 250),
+		StravaIncludePrivate:  getEnvBool("STRAVA_INCLUDE_PRIVATE", false),
+		StravaVisibility:      getEnvList("STRAVA_VISIBILITY"),
+		StravaIncludeCommutes: getEnvBool("STRAVA_INCLUDE_COMMUTES", false),
+		StravaIncludeHidden:   getEnvBool("STRAVA_INCLUDE_HIDDEN", false),
+		StravaRedactPatterns:  getEnvLines("STRAVA_REDACT_PATTERNS"),
+		StravaRoundStartMins:  getEnvInt("STRAVA_ROUND_START_MINUTES", 0),
+		StravaDropHeartrate:   getEnvBool("STRAVA_DROP_HEARTRATE", false),
+		StravaDisciplinesFile: os.Getenv("STRAVA_DISCIPLINES_FILE"),
 
 		LinkedInEmail:      os.Getenv("LINKEDIN_EMAIL"),
 		LinkedInPassword:   os.Getenv("LINKEDIN_PASSWORD"),
@@ -62,6 +130,55 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvList splits a comma-separated environment variable, dropping empty entries
+func getEnvList(key string) []string {
+	values := make([]string, 0)
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvLines splits a newline-separated environment variable, dropping
+// empty lines. Used for values that may contain commas, like regular
+// expressions.
+func getEnvLines(key string) []string {
+	values := make([]string, 0)
+	for _, value := range strings.Split(os.Getenv(key), "\n") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvBool parses a boolean environment variable ("true", "1", "false", ...)
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvInt parses a positive integer environment variable
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvNonNegativeInt parses an integer environment variable where 0 is a
+// valid setting, e.g. to disable a feature
+func getEnvNonNegativeInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value >= 0 {
+		return value
+	}
+	return defaultValue
+}
+
 func getEnvDuration(key string, defaultHours int) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if hours, err := strconv.Atoi(value); err == nil && hours > 0 {
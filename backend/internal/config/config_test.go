@@ -33,6 +33,113 @@ func TestLoad(t *testing.T) {
 	}
 }
 
+func TestLoadGitHubSources(t *testing.T) {
+	t.Setenv("GITHUB_SOURCES", "org:acme, friend ,,acme/tool")
+
+	cfg := Load()
+
+	expected := []string{"org:acme", "friend", "acme/tool"}
+	if len(cfg.GitHubSources) != len(expected) {
+		t.Fatalf("Expected %d sources, got %v", len(expected), cfg.GitHubSources)
+	}
+	for i, source := range expected {
+		if cfg.GitHubSources[i] != source {
+			t.Errorf("Source %d: expected %q, got %q", i, source, cfg.GitHubSources[i])
+		}
+	}
+}
+
+func TestLoadGitHubInclusionRules(t *testing.T) {
+	t.Setenv("GITHUB_INCLUDE_FORKS", "true")
+	t.Setenv("GITHUB_INCLUDE_ARCHIVED", "not-a-bool")
+	t.Setenv("GITHUB_TOPICS_DENY", "wip, homework")
+	t.Setenv("GITHUB_REPO_EXCLUDE", "acme/*,*-old")
+
+	cfg := Load()
+
+	if !cfg.GitHubIncludeForks {
+		t.Error("Expected forks to be included")
+	}
+	if cfg.GitHubIncludeArchived {
+		t.Error("Expected an invalid boolean to fall back to false")
+	}
+	if cfg.GitHubIncludeTemplates {
+		t.Error("Expected templates to be excluded by default")
+	}
+	if cfg.GitHubLegacyEmoji {
+		t.Error("Expected the legacy emoji marker to be disabled by default")
+	}
+	if len(cfg.GitHubTopicsDeny) != 2 || cfg.GitHubTopicsDeny[1] != "homework" {
+		t.Errorf("Expected denied topics [wip homework], got %v", cfg.GitHubTopicsDeny)
+	}
+	if len(cfg.GitHubRepoExclude) != 2 || cfg.GitHubRepoExclude[0] != "acme/*" {
+		t.Errorf("Expected exclude globs [acme/* *-old], got %v", cfg.GitHubRepoExclude)
+	}
+	if len(cfg.GitHubTopicsAllow) != 0 || len(cfg.GitHubRepoInclude) != 0 {
+		t.Errorf("Expected no allow lists, got %v and %v", cfg.GitHubTopicsAllow, cfg.GitHubRepoInclude)
+	}
+}
+
+func TestLoadGitHubWorkers(t *testing.T) {
+	t.Setenv("GITHUB_WORKERS", "16")
+	if cfg := Load(); cfg.GitHubWorkers != 16 {
+		t.Errorf("Expected 16 workers, got %d", cfg.GitHubWorkers)
+	}
+
+	t.Setenv("GITHUB_WORKERS", "0")
+	if cfg := Load(); cfg.GitHubWorkers != 8 {
+		t.Errorf("Expected a non-positive value to fall back to 8, got %d", cfg.GitHubWorkers)
+	}
+}
+
+func TestLoadGitHubReadmeExcerpt(t *testing.T) {
+	t.Setenv("GITHUB_README_EXCERPT", "true")
+	if cfg := Load(); !cfg.GitHubReadmeExcerpt {
+		t.Error("Expected README excerpts to be enabled")
+	}
+}
+
+func TestLoadStravaRecords(t *testing.T) {
+	t.Setenv("STRAVA_PR_DISTANCES", "5k, Half-Marathon")
+	t.Setenv("STRAVA_DETAIL_REQUESTS", "20")
+
+	cfg := Load()
+	if len(cfg.StravaRecordDistances) != 2 || cfg.StravaRecordDistances[1] != "Half-Marathon" {
+		t.Errorf("Expected two record distances, got %v", cfg.StravaRecordDistances)
+	}
+	if cfg.StravaDetailRequests != 20 {
+		t.Errorf("Expected 20 detail requests, got %d", cfg.StravaDetailRequests)
+	}
+
+	t.Setenv("STRAVA_DETAIL_REQUESTS", "0")
+	if cfg := Load(); cfg.StravaDetailRequests != 0 {
+		t.Errorf("Expected 0 to disable detail requests, got %d", cfg.StravaDetailRequests)
+	}
+
+	t.Setenv("STRAVA_DETAIL_REQUESTS", "-1")
+	if cfg := Load(); cfg.StravaDetailRequests != 50 {
+		t.Errorf("Expected a negative value to fall back to 50, got %d", cfg.StravaDetailRequests)
+	}
+}
+
+func TestLoadStravaPrivacy(t *testing.T) {
+	t.Setenv("STRAVA_VISIBILITY", "everyone,followers_only")
+	t.Setenv("STRAVA_REDACT_PATTERNS", "(?i)home\n\n  ^Run \\d{1,3}$\r\n")
+	t.Setenv("STRAVA_ROUND_START_MINUTES", "60")
+	t.Setenv("STRAVA_DROP_HEARTRATE", "true")
+
+	cfg := Load()
+	if len(cfg.StravaVisibility) != 2 || len(cfg.StravaRedactPatterns) != 2 || cfg.StravaRedactPatterns[1] != `^Run \d{1,3}$` {
+		t.Errorf("Unexpected visibility %v or patterns %v", cfg.StravaVisibility, cfg.StravaRedactPatterns)
+	}
+	if cfg.StravaRoundStartMins != 60 || !cfg.StravaDropHeartrate {
+		t.Errorf("Expected hourly start times without heart rate, got %d %v", cfg.StravaRoundStartMins, cfg.StravaDropHeartrate)
+	}
+	if cfg.StravaIncludePrivate || cfg.StravaIncludeCommutes || cfg.StravaIncludeHidden {
+		t.Error("Expected private, commute and hidden activities to be excluded by default")
+	}
+}
+
 func TestLoadDefaults(t *testing.T) {
 	// Ensure env vars are not set
 	if err := os.Unsetenv("PORT"); err != nil {
@@ -52,6 +159,39 @@ func TestLoadDefaults(t *testing.T) {
 		t.Errorf("Expected default GitHub username 'mrcodeeu', got %s", cfg.GitHubUsername)
 	}
 
+	if cfg.GitHubMode != "rest" {
+		t.Errorf("Expected default GitHub mode 'rest', got %s", cfg.GitHubMode)
+	}
+
+	if cfg.GitHubWorkers != 8 {
+		t.Errorf("Expected 8 GitHub workers by default, got %d", cfg.GitHubWorkers)
+	}
+
+	if cfg.GitHubReadmeExcerpt {
+		t.Error("Expected README excerpts to be disabled by default")
+	}
+
+	if cfg.GitHubActivityEvents != 30 {
+		t.Errorf("Expected 30 activity events by default, got %d", cfg.GitHubActivityEvents)
+	}
+
+	if cfg.StravaFullSync {
+		t.Error("Expected incremental Strava syncs by default")
+	}
+
+	if cfg.StravaDetailRequests != 50 {
+		t.Errorf("Expected 50 Strava detail requests by default, got %d", cfg.StravaDetailRequests)
+	}
+
+	if cfg.StravaTrendMonths != 12 || cfg.StravaTimezone != "UTC" {
+		t.Errorf("Expected 12 trend months in UTC by default, got %d %s", cfg.StravaTrendMonths, cfg.StravaTimezone)
+	}
+
+	if cfg.StravaRoutePoints != 100 || cfg.StravaRouteLimit != 50 || cfg.StravaHeatmapCell != 250 {
+		t.Errorf("Unexpected route defaults: %d points, %d routes, %d m cells",
+			cfg.StravaRoutePoints, cfg.StravaRouteLimit, cfg.StravaHeatmapCell)
+	}
+
 	if cfg.CacheTTLHours != 24 {
 		t.Errorf("Expected cache TTL 24 hours, got %d", cfg.CacheTTLHours)
 	}
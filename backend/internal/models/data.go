@@ -10,6 +10,33 @@ type GeneratedData struct {
 	Data        interface{} `json:"data"`
 }
 
+// ImageAsset describes an image mirrored next to the generated data
+type ImageAsset struct {
+	URL    string `json:"url"`    // local URL, e.g. /assets/3f2a9c...png
+	Source string `json:"source"` // original remote URL
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+	MIME   string `json:"mime"`
+	Hash   string `json:"hash"` // SHA-256 of the file content, hex encoded
+	Size   int64  `json:"size"` // bytes
+
+	// Variants are downscaled copies for srcset, smallest first; images
+	// narrower than a variant width are not upscaled
+	Variants []ImageVariant `json:"variants,omitempty"`
+	// Placeholder is a tiny base64 data URI meant to be shown blurred while loading
+	Placeholder string `json:"placeholder,omitempty"`
+	// DominantColor is the most common color as #rrggbb
+	DominantColor string `json:"dominant_color,omitempty"`
+}
+
+// ImageVariant is a resized copy of an ImageAsset
+type ImageVariant struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Size   int64  `json:"size"` // bytes
+}
+
 // StravaData contains all Strava-related data
 type StravaData struct {
 	TotalStats       StravaStats        `json:"total_stats"`
@@ -17,19 +44,103 @@ type StravaData struct {
 	RecentActivities []StravaActivity   `json:"recent_activities"`
 	BestActivities   StravaBestRecords  `json:"best_activities"`
 	PersonalRecords  []StravaRecord     `json:"personal_records"`
+	Races            []StravaActivity   `json:"races"` // activities marked as races, newest first
 	Disciplines      []StravaDiscipline `json:"disciplines"`
+	Trends           StravaTrends       `json:"trends"`
+	Maps             StravaMaps         `json:"maps"`
+	Gear             []StravaGear       `json:"gear"` // active first, then by distance
+}
+
+// StravaGear is a pair of shoes or a bike with its usage
+type StravaGear struct {
+	ID               string     `json:"id"`
+	Name             string     `json:"name"`
+	Kind             string     `json:"kind"` // "shoes" or "bike"
+	Brand            string     `json:"brand,omitempty"`
+	Model            string     `json:"model,omitempty"`
+	Primary          bool       `json:"primary"`
+	Retired          bool       `json:"retired"`
+	Distance         float64    `json:"distance"`          // meters, as tracked by Strava
+	ActivityCount    int        `json:"activity_count"`    // published activities with this gear
+	ActivityDistance float64    `json:"activity_distance"` // meters, over those activities
+	MovingTime       int        `json:"moving_time"`       // seconds, over those activities
+	LastUsed         *time.Time `json:"last_used,omitempty"`
+}
+
+// StravaMaps contains activity routes and a heatmap with points inside
+// privacy zones removed
+type StravaMaps struct {
+	Routes  GeoJSONFeatureCollection `json:"routes"` // one feature per recent activity
+	Heatmap StravaHeatmap            `json:"heatmap"`
+}
+
+// GeoJSONFeatureCollection is a GeoJSON FeatureCollection (RFC 7946)
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"` // "FeatureCollection"
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// GeoJSONFeature is a GeoJSON Feature
+type GeoJSONFeature struct {
+	Type       string          `json:"type"` // "Feature"
+	Geometry   GeoJSONGeometry `json:"geometry"`
+	Properties map[string]any  `json:"properties"`
+}
+
+// GeoJSONGeometry is a GeoJSON geometry; coordinates are [lng, lat]
+type GeoJSONGeometry struct {
+	Type        string `json:"type"` // "LineString" or "MultiLineString"
+	Coordinates any    `json:"coordinates"`
+}
+
+// StravaHeatmap counts how many activities passed through each grid cell
+type StravaHeatmap struct {
+	CellSize float64             `json:"cell_size"` // degrees
+	Cells    []StravaHeatmapCell `json:"cells"`     // by count, descending
+}
+
+// StravaHeatmapCell is a heatmap grid cell
+type StravaHeatmapCell struct {
+	Lat   float64 `json:"lat"` // cell center
+	Lng   float64 `json:"lng"`
+	Count int     `json:"count"` // activities passing through
+}
+
+// StravaTrends contains training volume per week and month, oldest first
+type StravaTrends struct {
+	Timezone string         `json:"timezone"` // IANA zone in which periods start
+	Weekly   []StravaPeriod `json:"weekly"`   // weeks start on Monday
+	Monthly  []StravaPeriod `json:"monthly"`
+}
+
+// StravaPeriod aggregates the activities of one week or month
+type StravaPeriod struct {
+	Start       string                 `json:"start"` // first day, "2006-01-02"
+	Total       StravaStats            `json:"total"`
+	Disciplines map[string]StravaStats `json:"disciplines"` // by discipline type; absent if no activities
 }
 
 // StravaDiscipline aggregates per-sport statistics and recent activities
 type StravaDiscipline struct {
-	// Type is a stable identifier: "running", "cycling", "training"
+	// Type is the configured discipline ID, e.g. "cycling" or "training"
 	Type          string           `json:"type"`
 	Label         string           `json:"label"`          // display name
 	Count         int              `json:"count"`          // total activity count
 	TotalTime     int              `json:"total_time"`     // seconds
 	TotalDistance float64          `json:"total_distance"` // meters; 0 for no-distance sports
+	HasDistance   bool             `json:"has_distance"`   // whether distance matters for the discipline
 	AvgHeartrate  float64          `json:"avg_heartrate"`  // bpm average across all activities; 0 if unavailable
 	Activities    []StravaActivity `json:"activities"`     // 5 most recent
+	Bests         []StravaBest     `json:"bests,omitempty"`
+}
+
+// StravaBest is the best activity of a discipline by one metric
+type StravaBest struct {
+	Type     string         `json:"type"`  // "longest_distance", "most_elevation", "fastest_20k", "max_power", etc.
+	Label    string         `json:"label"` // display name
+	Value    float64        `json:"value"` // in Unit
+	Unit     string         `json:"unit"`  // "m", "s", "m/s" or "W"
+	Activity StravaActivity `json:"activity"`
 }
 
 // StravaStats contains aggregate statistics
@@ -49,7 +160,7 @@ type StravaActivity struct {
 	MovingTime         int       `json:"moving_time"`          // seconds
 	ElapsedTime        int       `json:"elapsed_time"`         // seconds
 	TotalElevationGain float64   `json:"total_elevation_gain"` // meters
-	Type               string    `json:"type"`
+	Type               string    `json:"type"`                 // Strava sport type, e.g. "TrailRun" or "GravelRide"
 	StartDate          time.Time `json:"start_date"`
 	AveragePace        float64   `json:"average_pace"`  // min/km
 	AverageSpeed       float64   `json:"average_speed"` // m/s
@@ -57,6 +168,12 @@ type StravaActivity struct {
 	AverageHeartrate   float64   `json:"average_heartrate,omitempty"`
 	MaxHeartrate       float64   `json:"max_heartrate,omitempty"`
 	Calories           float64   `json:"calories,omitempty"` // kcal
+	AverageWatts       float64   `json:"average_watts,omitempty"`
+	MaxWatts           float64   `json:"max_watts,omitempty"`
+	WorkoutType        string    `json:"workout_type,omitempty"` // "race", "long_run" or "workout"
+	Race               bool      `json:"race,omitempty"`
+	DeviceName         string    `json:"device_name,omitempty"`
+	GearID             string    `json:"gear_id,omitempty"`
 }
 
 // StravaBestRecords contains best/longest activities
@@ -69,10 +186,12 @@ type StravaBestRecords struct {
 
 // StravaRecord represents a personal record
 type StravaRecord struct {
-	Type     string         `json:"type"`     // "5k", "10k", "half_marathon", "marathon", etc.
-	Time     int            `json:"time"`     // seconds
-	Distance float64        `json:"distance"` // meters
+	Type     string         `json:"type"`           // "5k", "10k", "half_marathon", "marathon", etc.
+	Name     string         `json:"name,omitempty"` // Strava best effort name, e.g. "Half-Marathon"
+	Time     int            `json:"time"`           // seconds
+	Distance float64        `json:"distance"`       // meters
 	Date     time.Time      `json:"date"`
+	Source   string         `json:"source,omitempty"` // "best_effort" (part of a run) or "activity" (whole run)
 	Activity StravaActivity `json:"activity"`
 }
 
@@ -1,17 +1,27 @@
 package scrapers
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"net/http"
 	"cmp"
+	"path"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/mrcodeeu/homepage/internal/models"
 	"github.com/mrcodeeu/homepage/internal/storage"
 )
 
@@ -20,8 +30,95 @@ const (
 	portfolioFile   = ".portfolio"
 	cacheKeyGitHub  = "github_projects"
 	defaultCacheTTL = 1 * time.Hour
+
+	// Rate limit handling: GitHub answers 403/429 when the primary or
+	// secondary rate limit is exhausted. We retry a few times but never
+	// wait longer than githubMaxRetryWait for a single retry.
+	githubMaxRetries   = 3
+	githubBaseBackoff  = 5 * time.Second
+	githubMaxRetryWait = 2 * time.Minute
+
+	// githubMaxPages guards against endless Link header loops
+	githubMaxPages = 100
+
+	// Conditional request cache: ETag/Last-Modified validators and bodies are
+	// kept much longer than the project cache since a 304 revalidates them
+	cacheKeyGitHubHTTPPrefix = "github_http_"
+	conditionalCacheTTL      = 30 * 24 * time.Hour
+)
+
+// GitHub scraping backends selectable via SetMode
+const (
+	// GitHubModeREST lists repositories and fetches files through the REST API
+	GitHubModeREST = "rest"
+	// GitHubModeGraphQL fetches repositories and marker files in batched GraphQL queries
+	GitHubModeGraphQL = "graphql"
+)
+
+// readmeVariations are the README file names tried, in order
+var readmeVariations = []string{"README.md", "README.MD", "readme.md", "Readme.md", "README"}
+
+var (
+	// markdownImageRegex matches ![alt](url), ![alt](<url>) and ![alt](url "title")
+	markdownImageRegex = regexp.MustCompile(`!\[[^\]]*\]\(\s*(?:<([^>]+)>|([^)\s]+))(?:\s+["'][^"']*["'])?\s*\)`)
+	// htmlImageRegex matches the src attribute of HTML <img> tags
+	htmlImageRegex = regexp.MustCompile(`(?i)<img\s[^>]*?src\s*=\s*["']([^"']+)["']`)
+)
+
+// Kinds of GitHub sources projects are discovered from
+const (
+	GitHubSourceUser = "user"
+	GitHubSourceOrg  = "org"
+	GitHubSourceRepo = "repo"
 )
 
+// GitHubSource is an account or a single repository to discover projects from
+type GitHubSource struct {
+	Kind  string // GitHubSourceUser, GitHubSourceOrg or GitHubSourceRepo
+	Owner string
+	Repo  string // only set for GitHubSourceRepo
+}
+
+// String returns the source in the format accepted by ParseGitHubSource
+func (s GitHubSource) String() string {
+	switch s.Kind {
+	case GitHubSourceOrg:
+		return "org:" + s.Owner
+	case GitHubSourceRepo:
+		return s.Owner + "/" + s.Repo
+	default:
+		return s.Owner
+	}
+}
+
+// ParseGitHubSource parses a source entry: "name" for a user account,
+// "org:name" for an organization, or "owner/repo" for a single repository
+func ParseGitHubSource(entry string) (GitHubSource, error) {
+	entry = strings.TrimSpace(entry)
+
+	var source GitHubSource
+	switch {
+	case strings.HasPrefix(entry, "org:"):
+		source = GitHubSource{Kind: GitHubSourceOrg, Owner: strings.TrimPrefix(entry, "org:")}
+	case strings.Contains(entry, "/"):
+		owner, repo, _ := strings.Cut(entry, "/")
+		if !repoNameRegex.MatchString(repo) {
+			return GitHubSource{}, fmt.Errorf("invalid repository name in %q", entry)
+		}
+		source = GitHubSource{Kind: GitHubSourceRepo, Owner: owner, Repo: repo}
+	default:
+		source = GitHubSource{Kind: GitHubSourceUser, Owner: entry}
+	}
+
+	if err := validateUsername(source.Owner); err != nil {
+		return GitHubSource{}, fmt.Errorf("invalid source %q: %w", entry, err)
+	}
+	return source, nil
+}
+
+// repoNameRegex matches valid GitHub repository names
+var repoNameRegex = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
 // GitHubScraper implements the Scraper interface for GitHub repositories
 type GitHubScraper struct {
 	username string
@@ -29,6 +126,34 @@ type GitHubScraper struct {
 	cache    storage.Cache
 	cacheTTL time.Duration
 	client   *http.Client
+	apiBase  string
+	mode     string
+	sleep    func(context.Context, time.Duration) error
+	// workers bounds the number of repositories checked concurrently
+	workers int
+	// sources lists additional accounts and repositories besides username
+	sources []GitHubSource
+
+	// rules filter repositories before their markers are checked
+	rules InclusionRules
+	// legacyEmoji accepts a bare 🎨 anywhere in a README as a marker
+	legacyEmoji bool
+	// readmeExcerpt renders an HTML excerpt of each project's README
+	readmeExcerpt bool
+
+	// readmes memoizes README lookups for the duration of one Scrape
+	readmes *readmeMemo
+
+	// portfolioErrors collects invalid .portfolio files found during Scrape
+	portfolioErrors []*PortfolioValidationError
+	// decisions explains for every repository of the last Scrape whether it was published
+	decisions []RepoDecision
+	mu        sync.Mutex
+
+	// requests counts every HTTP request sent to the GitHub API (including retries)
+	requests atomic.Int64
+	// notModified counts responses served from the conditional request cache
+	notModified atomic.Int64
 }
 
 // NewGitHubScraper creates a new GitHub scraper
@@ -46,7 +171,107 @@ func NewGitHubScraper(username, token string, cache storage.Cache) *GitHubScrape
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		apiBase: githubAPIBase,
+		mode:    GitHubModeREST,
+		sleep:   sleepContext,
+		workers: defaultGitHubWorkers,
+	}
+}
+
+// SetMode selects the scraping backend (GitHubModeREST or GitHubModeGraphQL)
+func (g *GitHubScraper) SetMode(mode string) error {
+	switch mode {
+	case GitHubModeREST, GitHubModeGraphQL:
+		g.mode = mode
+		return nil
+	default:
+		return fmt.Errorf("unknown GitHub scraper mode %q (valid: %s, %s)", mode, GitHubModeREST, GitHubModeGraphQL)
+	}
+}
+
+// SetSources configures additional users, organizations and repositories to
+// discover projects from. The scraper's own username is always included.
+func (g *GitHubScraper) SetSources(sources []GitHubSource) {
+	g.sources = sources
+}
+
+// allSources returns the primary user followed by the configured sources
+func (g *GitHubScraper) allSources() []GitHubSource {
+	return append([]GitHubSource{{Kind: GitHubSourceUser, Owner: g.username}}, g.sources...)
+}
+
+// SetConcurrency sets how many repositories are checked and enriched in
+// parallel; values below 1 restore the default
+func (g *GitHubScraper) SetConcurrency(workers int) {
+	if workers < 1 {
+		workers = defaultGitHubWorkers
+	}
+	g.workers = workers
+}
+
+// SetLegacyEmojiMarker enables the legacy 🎨 README marker, which opts a
+// repository in whenever the emoji appears anywhere in its README
+func (g *GitHubScraper) SetLegacyEmojiMarker(enabled bool) {
+	g.legacyEmoji = enabled
+}
+
+// SetReadmeExcerpt enables rendering a sanitized HTML excerpt of every
+// project's README summary and highlights
+func (g *GitHubScraper) SetReadmeExcerpt(enabled bool) {
+	g.readmeExcerpt = enabled
+}
+
+// SetInclusionRules sets the rules repositories must pass before their
+// portfolio markers are checked
+func (g *GitHubScraper) SetInclusionRules(rules InclusionRules) error {
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+	g.rules = rules
+	return nil
+}
+
+// Decisions returns the per-repository decision log of the last Scrape
+func (g *GitHubScraper) Decisions() []RepoDecision {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return slices.Clone(g.decisions)
+}
+
+// decide logs the decision made for a repository and returns it for the
+// decision log
+func (g *GitHubScraper) decide(repo GitHubRepo, included bool, reason string) RepoDecision {
+	decision := RepoDecision{Repo: g.ownerOf(repo) + "/" + repo.Name, Included: included, Reason: reason}
+	if included {
+		log.Printf("  → Included %s: %s", decision.Repo, reason)
+	} else {
+		log.Printf("  → Skipped %s: %s", decision.Repo, reason)
 	}
+	return decision
+}
+
+// PortfolioErrors returns the .portfolio validation errors of the last Scrape
+func (g *GitHubScraper) PortfolioErrors() []*PortfolioValidationError {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return slices.Clone(g.portfolioErrors)
+}
+
+// recordPortfolioError remembers an invalid .portfolio file for reporting
+func (g *GitHubScraper) recordPortfolioError(err *PortfolioValidationError) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.portfolioErrors = append(g.portfolioErrors, err)
+}
+
+// RequestCount returns the number of GitHub API requests made so far
+func (g *GitHubScraper) RequestCount() int64 {
+	return g.requests.Load()
+}
+
+// NotModifiedCount returns the number of requests answered with 304 Not Modified
+func (g *GitHubScraper) NotModifiedCount() int64 {
+	return g.notModified.Load()
 }
 
 // validateUsername validates GitHub username format
@@ -73,40 +298,133 @@ type ProjectLink struct {
 	Icon string `json:"icon,omitempty"`
 }
 
+// ProjectRelease describes the latest published release of a project
+type ProjectRelease struct {
+	Tag         string    `json:"tag"`
+	Name        string    `json:"name,omitempty"`
+	URL         string    `json:"url"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// LanguageShare is one entry of a project's language breakdown
+type LanguageShare struct {
+	Name       string  `json:"name"`
+	Bytes      int     `json:"bytes"`
+	Percentage float64 `json:"percentage"` // 0-100, rounded to one decimal
+}
+
 // Project represents a GitHub project
 type Project struct {
-	Name        string        `json:"name"`
-	Description string        `json:"description"`
-	URL         string        `json:"url"`
-	Stars       int           `json:"stars"`
-	Language    string        `json:"language"`
-	Topics      []string      `json:"topics"`
-	Images      []string      `json:"images"`
-	Badges      []string      `json:"badges"`
-	Featured    bool          `json:"featured"`
-	Links       []ProjectLink `json:"links"`
-	Priority    int           `json:"priority"`
+	Name          string              `json:"name"`
+	Owner         string              `json:"owner"`
+	Description   string              `json:"description"`
+	URL           string              `json:"url"`
+	Homepage      string              `json:"homepage,omitempty"`
+	Stars         int                 `json:"stars"`
+	Forks         int                 `json:"forks"`
+	OpenIssues    int                 `json:"open_issues"`       // issues and pull requests
+	License       string              `json:"license,omitempty"` // SPDX identifier
+	PushedAt      time.Time           `json:"pushed_at"`
+	LatestRelease *ProjectRelease     `json:"latest_release,omitempty"`
+	Language      string              `json:"language"`
+	Languages     []LanguageShare     `json:"languages"` // sorted by size, largest first
+	Topics        []string            `json:"topics"`
+	Images        []string            `json:"images"`
+	ImageAssets   []models.ImageAsset `json:"image_assets,omitempty"` // mirrored copies of Images
+	Badges        []string            `json:"badges"`
+	Featured      bool                `json:"featured"`
+	Links         []ProjectLink       `json:"links"`
+	Priority      int                 `json:"priority"`
+	DisplayName   string              `json:"display_name,omitempty"`
+	Category      string              `json:"category,omitempty"`
+	OrderGroup    int                 `json:"order_group"`
+	StartDate     string              `json:"start_date,omitempty"`   // YYYY, YYYY-MM or YYYY-MM-DD
+	EndDate       string              `json:"end_date,omitempty"`     // empty while ongoing
+	Summary       string              `json:"summary,omitempty"`      // first prose paragraph of the README
+	Highlights    []string            `json:"highlights,omitempty"`   // "Features" list of the README
+	ExcerptHTML   string              `json:"excerpt_html,omitempty"` // sanitized summary and highlights
+}
+
+// UncategorizedCategory is the group name for projects without a category
+const UncategorizedCategory = "Other"
+
+// ProjectGroup is a category with its projects in display order
+type ProjectGroup struct {
+	Category string    `json:"category"`
+	Projects []Project `json:"projects"`
 }
 
 // PortfolioMetadata represents .portfolio file content
 type PortfolioMetadata struct {
-	Description string        `json:"description,omitempty"`
-	Images      []string      `json:"images,omitempty"`
-	Featured    bool          `json:"featured,omitempty"`
-	Tags        []string      `json:"tags,omitempty"`
-	Links       []ProjectLink `json:"links,omitempty"`
-	Priority    *int          `json:"priority,omitempty"`
+	Schema        string        `json:"$schema,omitempty"`
+	SchemaVersion int           `json:"schema_version,omitempty"`
+	Description   string        `json:"description,omitempty"`
+	Images        []string      `json:"images,omitempty"`
+	Featured      bool          `json:"featured,omitempty"`
+	Tags          []string      `json:"tags,omitempty"`
+	Links         []ProjectLink `json:"links,omitempty"`
+	Priority      *int          `json:"priority,omitempty"`
+	Hidden        bool          `json:"hidden,omitempty"`
+	Category      string        `json:"category,omitempty"`
+	OrderGroup    int           `json:"order_group,omitempty"`
+	DisplayName   string        `json:"display_name,omitempty"`
+	StartDate     string        `json:"start_date,omitempty"`
+	EndDate       string        `json:"end_date,omitempty"`
 }
 
 // GitHubRepo represents a GitHub repository from the API
 type GitHubRepo struct {
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	HTMLURL     string   `json:"html_url"`
-	Language    string   `json:"language"`
-	StarCount   int      `json:"stargazers_count"`
-	Topics      []string `json:"topics"`
-	Private     bool     `json:"private"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	HTMLURL       string   `json:"html_url"`
+	Language      string   `json:"language"`
+	StarCount     int      `json:"stargazers_count"`
+	Topics        []string `json:"topics"`
+	Private       bool     `json:"private"`
+	Fork          bool     `json:"fork"`
+	Archived      bool     `json:"archived"`
+	IsTemplate    bool     `json:"is_template"`
+	DefaultBranch string   `json:"default_branch"`
+	Owner         struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	Homepage        string         `json:"homepage"`
+	ForksCount      int            `json:"forks_count"`
+	OpenIssuesCount int            `json:"open_issues_count"`
+	PushedAt        time.Time      `json:"pushed_at"`
+	License         *githubLicense `json:"license"`
+}
+
+// githubLicense is the license detected by GitHub for a repository
+type githubLicense struct {
+	SPDXID string `json:"spdx_id"`
+}
+
+// repoCandidate is a repository being checked for portfolio markers together
+// with the marker-relevant file contents fetched for it so far
+type repoCandidate struct {
+	Repo GitHubRepo
+
+	// Prefetched is true when Portfolio and README were delivered with the
+	// repository listing (GraphQL mode); nil then means the file is missing.
+	// Otherwise they are fetched lazily through the REST API and memoized.
+	Prefetched bool
+	Portfolio  *string
+	README     *string
+	// PortfolioPath is the name of the marker file Portfolio was read from
+	PortfolioPath string
+	// READMEPath is the repository path of README, used to resolve relative links
+	READMEPath string
+
+	// rootFiles caches the names of the files in the repository root (REST mode)
+	rootFiles map[string]bool
+	rootErr   error
+
+	// Release and Languages are only meaningful when Prefetched is true;
+	// in REST mode they are fetched once the repository is known to be a
+	// portfolio project
+	Release   *ProjectRelease
+	Languages map[string]int
 }
 
 // GetCached returns cached projects or scrapes if needed
@@ -132,92 +450,250 @@ func (g *GitHubScraper) GetCached() (any, error) {
 
 // Scrape fetches fresh data from GitHub
 func (g *GitHubScraper) Scrape() (any, error) {
-	log.Printf("Fetching repositories for user: %s", g.username)
+	return g.ScrapeContext(context.Background())
+}
+
+// ScrapeContext fetches fresh data from GitHub. Repositories are checked and
+// enriched by a bounded pool of workers (see SetConcurrency); the result does
+// not depend on the order in which they finish. Cancelling ctx aborts
+// in-flight requests and returns the context's error.
+func (g *GitHubScraper) ScrapeContext(ctx context.Context) (any, error) {
+	log.Printf("Fetching repositories for user: %s and %d additional source(s) (mode: %s)",
+		g.username, len(g.sources), g.mode)
+
+	g.mu.Lock()
+	g.portfolioErrors = nil
+	g.decisions = nil
+	g.mu.Unlock()
+	g.readmes = newReadmeMemo()
 
 	// Get all repositories
-	repos, err := g.fetchRepositories()
+	candidates, err := g.fetchCandidates(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch repositories: %w", err)
 	}
 
-	log.Printf("Found %d total repositories for user %s", len(repos), g.username)
+	log.Printf("Found %d total repositories across all sources (checking with %d workers)",
+		len(candidates), min(g.workers, len(candidates)))
 
 	// Filter and enrich portfolio projects
-	projects := make([]Project, 0)
-	for i, repo := range repos {
-		log.Printf("[%d/%d] Checking repository: %s (private: %v)", i+1, len(repos), repo.Name, repo.Private)
+	results := g.processCandidates(ctx, candidates)
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("scrape cancelled: %w", err)
+	}
 
-		// Skip private repos
-		if repo.Private {
-			log.Printf("  → Skipped (private repository)")
-			continue
+	projects := make([]Project, 0)
+	decisions := make([]RepoDecision, 0, len(results))
+	for _, result := range results {
+		decisions = append(decisions, result.decision)
+		if result.project != nil {
+			projects = append(projects, *result.project)
 		}
+	}
+
+	sortProjects(projects)
 
-		// Check for portfolio marker
-		log.Printf("  → Checking for portfolio markers...")
-		hasMarker, metadata, err := g.checkPortfolioMarker(repo.Name)
+	g.mu.Lock()
+	g.decisions = decisions
+	// Workers record errors as they finish; report them in a stable order
+	slices.SortFunc(g.portfolioErrors, func(a, b *PortfolioValidationError) int {
+		return cmp.Compare(a.Repo, b.Repo)
+	})
+	g.mu.Unlock()
+
+	log.Printf("Total portfolio projects found: %d", len(projects))
+	log.Printf("GitHub API requests used: %d (%d unchanged, served from cache)",
+		g.RequestCount(), g.NotModifiedCount())
+	return projects, nil
+}
+
+// fetchCandidates lists repositories of every source using the configured
+// backend, dropping repositories reachable through more than one source
+func (g *GitHubScraper) fetchCandidates(ctx context.Context) ([]repoCandidate, error) {
+	useGraphQL := g.mode == GitHubModeGraphQL
+	if useGraphQL && g.token == "" {
+		log.Println("Warning: GraphQL mode requires GITHUB_TOKEN, falling back to REST")
+		useGraphQL = false
+	}
+
+	candidates := make([]repoCandidate, 0)
+	seen := make(map[string]bool)
+	for _, source := range g.allSources() {
+		var found []repoCandidate
+		var err error
+		if useGraphQL {
+			found, err = g.fetchSourceGraphQL(ctx, source)
+		} else {
+			found, err = g.fetchSourceREST(ctx, source)
+		}
 		if err != nil {
-			// Log error with context but continue to next repo
-			log.Printf("Warning: Failed to check portfolio marker for %s: %v", repo.Name, err)
-			continue
+			return nil, fmt.Errorf("source %s: %w", source, err)
 		}
 
-		if !hasMarker {
-			continue
+		added := 0
+		for _, c := range found {
+			key := strings.ToLower(g.ownerOf(c.Repo) + "/" + c.Repo.Name)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			candidates = append(candidates, c)
+			added++
 		}
+		log.Printf("Source %s: %d repositories (%d new)", source, len(found), added)
+	}
 
-		// Log found portfolio repo
-		log.Printf("Found portfolio repo: %s (featured: %v, %d images in metadata)",
-			repo.Name, metadata.Featured, len(metadata.Images))
+	return candidates, nil
+}
 
-		// Build project
-		project := Project{
-			Name:        repo.Name,
-			Description: repo.Description,
-			URL:         repo.HTMLURL,
-			Stars:       repo.StarCount,
-			Language:    repo.Language,
-			Topics:      repo.Topics,
-			Featured:    metadata.Featured,
-			Links:       metadata.Links,
+// fetchSourceREST lists the repositories of a single source via the REST API
+func (g *GitHubScraper) fetchSourceREST(ctx context.Context, source GitHubSource) ([]repoCandidate, error) {
+	var repos []GitHubRepo
+	switch source.Kind {
+	case GitHubSourceRepo:
+		repo, err := g.fetchRepository(ctx, source.Owner, source.Repo)
+		if err != nil {
+			return nil, err
 		}
-
-		if metadata.Priority != nil {
-			project.Priority = *metadata.Priority
+		repos = []GitHubRepo{*repo}
+	case GitHubSourceOrg:
+		var err error
+		repos, err = g.fetchRepositories(ctx, fmt.Sprintf("%s/orgs/%s/repos?type=public&per_page=100", g.apiBase, source.Owner))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		var err error
+		repos, err = g.fetchRepositories(ctx, fmt.Sprintf("%s/users/%s/repos?per_page=100", g.apiBase, source.Owner))
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		// Override description if provided in metadata
-		if metadata.Description != "" {
-			project.Description = metadata.Description
+	candidates := make([]repoCandidate, len(repos))
+	for i, repo := range repos {
+		if repo.Owner.Login == "" {
+			repo.Owner.Login = source.Owner
 		}
+		candidates[i] = repoCandidate{Repo: repo}
+	}
+	return candidates, nil
+}
 
-		// Merge images from metadata and README
-		images := make([]string, 0)
+// ownerOf returns the owner login of a repository, defaulting to the scraper's user
+func (g *GitHubScraper) ownerOf(repo GitHubRepo) string {
+	if repo.Owner.Login != "" {
+		return repo.Owner.Login
+	}
+	return g.username
+}
 
-		// Convert metadata images (from .portfolio) to absolute URLs if needed
-		for _, img := range metadata.Images {
-			images = append(images, g.normalizeImageURL(img, repo.Name))
-		}
+// buildProject turns a candidate into a Project. It returns nil if the
+// repository carries no portfolio marker.
+func (g *GitHubScraper) buildProject(ctx context.Context, c *repoCandidate) (*Project, string, error) {
+	repo := c.Repo
+
+	// Check for portfolio marker
+	log.Printf("  → Checking for portfolio markers...")
+	marker, metadata, err := g.checkPortfolioMarker(ctx, c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if marker == "" {
+		return nil, "no portfolio marker", nil
+	}
+
+	if metadata.Hidden {
+		return nil, fmt.Sprintf("hidden via %s", marker), nil
+	}
+
+	// Log found portfolio repo
+	log.Printf("Found portfolio repo: %s (featured: %v, %d images in metadata)",
+		repo.Name, metadata.Featured, len(metadata.Images))
+
+	// Build project
+	project := Project{
+		Name:        repo.Name,
+		Owner:       g.ownerOf(repo),
+		Description: repo.Description,
+		URL:         repo.HTMLURL,
+		Homepage:    repo.Homepage,
+		Stars:       repo.StarCount,
+		Forks:       repo.ForksCount,
+		OpenIssues:  repo.OpenIssuesCount,
+		PushedAt:    repo.PushedAt,
+		Language:    repo.Language,
+		Topics:      repo.Topics,
+		Featured:    metadata.Featured,
+		Links:       metadata.Links,
+		DisplayName: metadata.DisplayName,
+		Category:    metadata.Category,
+		OrderGroup:  metadata.OrderGroup,
+		StartDate:   metadata.StartDate,
+		EndDate:     metadata.EndDate,
+	}
+	if repo.License != nil && repo.License.SPDXID != "NOASSERTION" {
+		project.License = repo.License.SPDXID
+	}
+
+	g.enrichProject(ctx, c, &project)
+
+	if metadata.Priority != nil {
+		project.Priority = *metadata.Priority
+	}
+
+	// Override description if provided in metadata
+	if metadata.Description != "" {
+		project.Description = metadata.Description
+	}
 
-		// Try to extract images from README
-		readmeImages, err := g.extractImagesFromREADME(repo.Name)
-		if err == nil {
-			log.Printf("  Found %d images in README of %s", len(readmeImages), repo.Name)
-			images = append(images, readmeImages...)
+	// Merge images from metadata and README
+	images := make([]string, 0)
+
+	// Convert metadata images (from .portfolio) to absolute URLs if needed
+	for _, img := range metadata.Images {
+		if normalized := g.normalizeImageURL(img, repo, ""); normalized != "" {
+			images = append(images, normalized)
 		}
+	}
+
+	// Try to extract images from README
+	readmeImages, err := g.extractImagesFromREADME(ctx, c)
+	if err == nil {
+		log.Printf("  Found %d images in README of %s", len(readmeImages), repo.Name)
+		images = append(images, readmeImages...)
+	}
 
-		// Separate images and badges, then deduplicate
-		uniqueImages := deduplicateStrings(images)
-		project.Images, project.Badges = separateImagesAndBadges(uniqueImages)
-		log.Printf("  Total unique images for %s: %d (+ %d badges)", repo.Name, len(project.Images), len(project.Badges))
+	// Separate images and badges, then deduplicate
+	uniqueImages := deduplicateStrings(images)
+	project.Images, project.Badges = separateImagesAndBadges(uniqueImages)
+	log.Printf("  Total unique images for %s: %d (+ %d badges)", repo.Name, len(project.Images), len(project.Badges))
 
-		projects = append(projects, project)
+	// Summarize the README for project cards
+	if readme, err := g.readmeContent(ctx, c); err == nil {
+		g.applyReadmeDigest(c, &project, digestReadme(readme))
 	}
 
-	sortProjects(projects)
+	return &project, fmt.Sprintf("marked via %s", marker), nil
+}
 
-	log.Printf("Total portfolio projects found: %d", len(projects))
-	return projects, nil
+// applyReadmeDigest stores the README summary and highlights on a project.
+// The summary also replaces a missing description.
+func (g *GitHubScraper) applyReadmeDigest(c *repoCandidate, project *Project, digest readmeDigest) {
+	project.Summary = digest.Summary()
+	project.Highlights = digest.Highlights()
+	if project.Description == "" {
+		project.Description = project.Summary
+	}
+
+	if g.readmeExcerpt {
+		baseDir := path.Dir(c.READMEPath)
+		project.ExcerptHTML = digest.ExcerptHTML(func(target string) string {
+			return g.resolveReadmeLink(target, c.Repo, baseDir)
+		})
+	}
+	log.Printf("  README summary for %s: %d characters, %d highlights", project.Name, len(project.Summary), len(project.Highlights))
 }
 
 // Refresh forces a fresh scrape and updates cache
@@ -241,24 +717,215 @@ func (g *GitHubScraper) Refresh() (any, error) {
 	return projects, nil
 }
 
-// fetchRepositories gets all repositories for the user
-func (g *GitHubScraper) fetchRepositories() ([]GitHubRepo, error) {
-	url := fmt.Sprintf("%s/users/%s/repos?per_page=100", githubAPIBase, g.username)
+// fetchRepositories gets all repositories of a listing endpoint, following
+// the Link header until every page has been fetched
+func (g *GitHubScraper) fetchRepositories(ctx context.Context, listURL string) ([]GitHubRepo, error) {
+	next := listURL
+	startRequests := g.RequestCount()
+
+	repos := make([]GitHubRepo, 0)
+	pages := 0
+	for next != "" {
+		if pages >= githubMaxPages {
+			log.Printf("Warning: stopped after %d pages of repositories (page limit reached)", pages)
+			break
+		}
+
+		page, nextURL, err := g.fetchRepositoryPage(ctx, next)
+		if err != nil {
+			return nil, fmt.Errorf("page %d (after %d repositories, %d requests): %w",
+				pages+1, len(repos), g.RequestCount()-startRequests, err)
+		}
+
+		pages++
+		repos = append(repos, page...)
+		next = nextURL
+	}
+
+	log.Printf("Fetched %d repositories across %d page(s) using %d request(s)",
+		len(repos), pages, g.RequestCount()-startRequests)
+	return repos, nil
+}
+
+// enrichProject adds the latest release and language breakdown to a project.
+// Failures only cost the extra details, never the project itself.
+func (g *GitHubScraper) enrichProject(ctx context.Context, c *repoCandidate, project *Project) {
+	release, languages := c.Release, c.Languages
+	if !c.Prefetched {
+		var err error
+		if release, err = g.fetchLatestRelease(ctx, project.Owner, project.Name); err != nil {
+			log.Printf("  Warning: failed to fetch latest release of %s: %v", project.Name, err)
+		}
+		if languages, err = g.fetchLanguages(ctx, project.Owner, project.Name); err != nil {
+			log.Printf("  Warning: failed to fetch languages of %s: %v", project.Name, err)
+		}
+	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	project.LatestRelease = release
+	project.Languages = languageBreakdown(languages)
+}
+
+// fetchLatestRelease gets the latest published release; nil if there is none
+func (g *GitHubScraper) fetchLatestRelease(ctx context.Context, owner, repoName string) (*ProjectRelease, error) {
+	resp, err := g.get(ctx, fmt.Sprintf("%s/repos/%s/%s/releases/latest", g.apiBase, owner, repoName), "application/vnd.github.v3+json")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	// Add authentication if token is provided
-	if g.token != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", g.token))
+	var release struct {
+		TagName     string    `json:"tag_name"`
+		Name        string    `json:"name"`
+		HTMLURL     string    `json:"html_url"`
+		PublishedAt time.Time `json:"published_at"`
+	}
+	if err := json.Unmarshal(resp.Body, &release); err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+
+	return &ProjectRelease{
+		Tag:         release.TagName,
+		Name:        release.Name,
+		URL:         release.HTMLURL,
+		PublishedAt: release.PublishedAt,
+	}, nil
+}
+
+// fetchLanguages gets the number of bytes written in each language
+func (g *GitHubScraper) fetchLanguages(ctx context.Context, owner, repoName string) (map[string]int, error) {
+	resp, err := g.get(ctx, fmt.Sprintf("%s/repos/%s/%s/languages", g.apiBase, owner, repoName), "application/vnd.github.v3+json")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	resp, err := g.client.Do(req)
+	var languages map[string]int
+	if err := json.Unmarshal(resp.Body, &languages); err != nil {
+		return nil, fmt.Errorf("failed to decode languages: %w", err)
+	}
+	return languages, nil
+}
+
+// languageBreakdown converts byte counts into shares sorted by size
+func languageBreakdown(languages map[string]int) []LanguageShare {
+	total := 0
+	for _, bytes := range languages {
+		total += bytes
+	}
+
+	shares := make([]LanguageShare, 0, len(languages))
+	if total == 0 {
+		return shares
+	}
+	for name, bytes := range languages {
+		shares = append(shares, LanguageShare{
+			Name:       name,
+			Bytes:      bytes,
+			Percentage: math.Round(float64(bytes)*1000/float64(total)) / 10,
+		})
+	}
+
+	slices.SortFunc(shares, func(a, b LanguageShare) int {
+		if c := cmp.Compare(b.Bytes, a.Bytes); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return shares
+}
+
+// fetchRepository gets a single repository by owner and name
+func (g *GitHubScraper) fetchRepository(ctx context.Context, owner, name string) (*GitHubRepo, error) {
+	resp, err := g.get(ctx, fmt.Sprintf("%s/repos/%s/%s", g.apiBase, owner, name), "application/vnd.github.v3+json")
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch repositories: %w", err)
+		return nil, fmt.Errorf("failed to fetch repository: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(resp.Body))
+	}
+
+	var repo GitHubRepo
+	if err := json.Unmarshal(resp.Body, &repo); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &repo, nil
+}
+
+// fetchRepositoryPage fetches a single page of repositories and returns the
+// URL of the next page ("" if this was the last one)
+func (g *GitHubScraper) fetchRepositoryPage(ctx context.Context, url string) ([]GitHubRepo, string, error) {
+	resp, err := g.get(ctx, url, "application/vnd.github.v3+json")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch repositories: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(resp.Body))
+	}
+
+	var repos []GitHubRepo
+	if err := json.Unmarshal(resp.Body, &repos); err != nil {
+		return nil, "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return repos, parseNextLink(resp.Header.Get("Link")), nil
+}
+
+// githubResponse is a fully read GitHub API response
+type githubResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// Cached is true when GitHub answered 304 and Body came from the cache
+	Cached bool
+}
+
+// conditionalEntry stores the validators and body of a previous 200 response
+type conditionalEntry struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Link         string `json:"link,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+// conditionalCacheKey derives the cache key for a URL and Accept header.
+// The URL is hashed since file cache keys become file names.
+func conditionalCacheKey(url, accept string) string {
+	sum := sha256.Sum256([]byte(accept + " " + url))
+	return cacheKeyGitHubHTTPPrefix + hex.EncodeToString(sum[:16])
+}
+
+// get performs a conditional GET: if a previous response for the URL is
+// cached, its ETag/Last-Modified are sent and a 304 reuses the cached body,
+// which does not count against the GitHub rate limit.
+func (g *GitHubScraper) get(ctx context.Context, url, accept string) (*githubResponse, error) {
+	key := conditionalCacheKey(url, accept)
+	entry := g.loadConditionalEntry(key)
+
+	req, err := g.newRequest(ctx, url, accept)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := g.do(req)
+	if err != nil {
+		return nil, err
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -266,89 +933,274 @@ func (g *GitHubScraper) fetchRepositories() ([]GitHubRepo, error) {
 		}
 	}()
 
-	if resp.StatusCode != http.StatusOK {
-		body, readErr := io.ReadAll(resp.Body)
-		if readErr != nil {
-			return nil, fmt.Errorf("GitHub API returned status %d (failed to read body)", resp.StatusCode)
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		g.notModified.Add(1)
+		header := resp.Header.Clone()
+		if entry.Link != "" {
+			header.Set("Link", entry.Link)
 		}
-		return nil, fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(body))
+		return &githubResponse{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       entry.Body,
+			Cached:     true,
+		}, nil
 	}
 
-	var repos []GitHubRepo
-	if err := json.NewDecoder(resp.Body).Decode(&repos); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	return repos, nil
+	if resp.StatusCode == http.StatusOK {
+		g.storeConditionalEntry(key, conditionalEntry{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Link:         resp.Header.Get("Link"),
+			Body:         body,
+		})
+	}
+
+	return &githubResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}, nil
+}
+
+// loadConditionalEntry returns the cached entry for key, or nil if there is none
+func (g *GitHubScraper) loadConditionalEntry(key string) *conditionalEntry {
+	if g.cache == nil {
+		return nil
+	}
+	data, err := g.cache.Get(key)
+	if err != nil {
+		log.Printf("Warning: failed to read conditional request cache: %v", err)
+		return nil
+	}
+	if data == nil {
+		return nil
+	}
+	var entry conditionalEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	if entry.ETag == "" && entry.LastModified == "" {
+		return nil
+	}
+	return &entry
+}
+
+// storeConditionalEntry caches a response body along with its validators.
+// Responses without validators are not worth caching.
+func (g *GitHubScraper) storeConditionalEntry(key string, entry conditionalEntry) {
+	if g.cache == nil || (entry.ETag == "" && entry.LastModified == "") {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("Warning: failed to marshal conditional request cache entry: %v", err)
+		return
+	}
+	if err := g.cache.Set(key, data, conditionalCacheTTL); err != nil {
+		log.Printf("Warning: failed to update conditional request cache: %v", err)
+	}
+}
+
+// newRequest creates a GET request against the GitHub API with auth and Accept headers set
+func (g *GitHubScraper) newRequest(ctx context.Context, url, accept string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Add authentication if token is provided
+	if g.token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", g.token))
+	}
+	req.Header.Set("Accept", accept)
+
+	return req, nil
+}
+
+// do sends a request to the GitHub API, backing off and retrying when
+// GitHub reports that the rate limit has been exceeded. The final response
+// is returned as-is so callers can inspect the status code.
+func (g *GitHubScraper) do(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		g.requests.Add(1)
+		resp, err := g.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
+			if n, convErr := strconv.Atoi(remaining); convErr == nil && n > 0 && n < 10 {
+				log.Printf("Warning: GitHub rate limit almost exhausted (%d requests remaining)", n)
+			}
+		}
+
+		wait, limited := rateLimitWait(resp, attempt, time.Now())
+		if !limited {
+			return resp, nil
+		}
+		if attempt >= githubMaxRetries {
+			log.Printf("Warning: GitHub rate limit still exceeded after %d retries", attempt)
+			return resp, nil
+		}
+		if wait > githubMaxRetryWait {
+			log.Printf("Warning: GitHub rate limit resets in %v, not waiting", wait.Round(time.Second))
+			return resp, nil
+		}
+
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Printf("Warning: failed to close response body: %v", closeErr)
+		}
+		// Rewind request bodies (GraphQL POSTs) before resending
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", bodyErr)
+			}
+			req.Body = body
+		}
+		log.Printf("GitHub rate limit hit (status %d), retrying in %v (attempt %d/%d)",
+			resp.StatusCode, wait, attempt+1, githubMaxRetries)
+		if err := g.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// rateLimitWait reports whether a response signals a rate limit and how long
+// to wait before retrying. Retry-After takes precedence, then X-RateLimit-Reset,
+// then exponential backoff.
+func rateLimitWait(resp *http.Response, attempt int, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			wait := time.Unix(reset, 0).Sub(now) + time.Second
+			if wait < 0 {
+				wait = 0
+			}
+			return wait, true
+		}
+		return githubBaseBackoff << attempt, true
+	}
+
+	// A 403 without rate limit headers is a permission problem, not throttling
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return githubBaseBackoff << attempt, true
+	}
+	return 0, false
+}
+
+// parseNextLink extracts the rel="next" URL from a GitHub Link header
+func parseNextLink(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+		for _, param := range segments[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(segments[0]), "<>")
+			}
+		}
+	}
+	return ""
 }
 
-// checkPortfolioMarker checks if a repository has a portfolio marker
-func (g *GitHubScraper) checkPortfolioMarker(repoName string) (bool, PortfolioMetadata, error) {
-	// Try to fetch .portfolio file
-	content, err := g.fetchFileContent(repoName, portfolioFile)
+// checkPortfolioMarker checks if a repository has a portfolio marker and
+// returns a description of the marker found, or "" if there is none
+func (g *GitHubScraper) checkPortfolioMarker(ctx context.Context, c *repoCandidate) (string, PortfolioMetadata, error) {
+	repoName := c.Repo.Name
+
+	// Try to fetch a .portfolio file (JSON, YAML or TOML)
+	name, content, err := g.portfolioContent(ctx, c)
 	if err == nil {
-		log.Printf("    Found .portfolio file in %s", repoName)
-		// Parse .portfolio JSON
-		var metadata PortfolioMetadata
-		if unmarshalErr := json.Unmarshal([]byte(content), &metadata); unmarshalErr != nil {
-			log.Printf("    Warning: Invalid .portfolio JSON in %s: %v", repoName, unmarshalErr)
-			// Invalid JSON in .portfolio is a real error we should report
-			return false, PortfolioMetadata{}, fmt.Errorf("invalid .portfolio file: %w", unmarshalErr)
+		log.Printf("    Found %s file in %s", name, repoName)
+		metadata, parseErr := parsePortfolioFile(name, content)
+		if parseErr != nil {
+			// An invalid marker file is a real error we should report
+			g.reportInvalidPortfolio(c, name, parseErr)
+			return "", PortfolioMetadata{}, parseErr
 		}
-		log.Printf("    ✓ Valid .portfolio metadata loaded")
-		return true, metadata, nil
+		log.Printf("    ✓ Valid %s metadata loaded (schema version %d)", name, metadata.SchemaVersion)
+		return name + " file", metadata, nil
 	}
 
 	// If .portfolio doesn't exist, check README for marker
 	log.Printf("    No .portfolio file, checking README...")
-	readme, readmeErr := g.fetchREADME(repoName)
+	readme, readmeErr := g.readmeContent(ctx, c)
 	if readmeErr != nil {
 		// If both .portfolio and README don't exist or can't be fetched,
 		// this repo simply doesn't have a portfolio marker - not an error
-		return false, PortfolioMetadata{}, nil
+		return "", PortfolioMetadata{}, nil
 	}
 
-	// Check for <!-- PORTFOLIO --> comment or 🎨 emoji
-	hasHTMLComment := strings.Contains(readme, "<!-- PORTFOLIO -->")
-	hasEmojiMarker := strings.Contains(readme, "🎨")
+	readmeName := c.READMEPath
+	if readmeName == "" {
+		readmeName = "README"
+	}
 
-	if hasHTMLComment {
-		log.Printf("    ✓ Found <!-- PORTFOLIO --> marker in README")
-		return true, PortfolioMetadata{}, nil
+	// Check for front matter or a <!-- PORTFOLIO ... --> comment
+	kind, fields, err := parseReadmeMarker(readme)
+	if err != nil {
+		g.reportInvalidPortfolio(c, readmeName, err)
+		return "", PortfolioMetadata{}, err
+	}
+	if kind != "" {
+		source := readmeName + " " + kind
+		metadata, err := metadataFromFields(source, fields)
+		if err != nil {
+			g.reportInvalidPortfolio(c, source, err)
+			return "", PortfolioMetadata{}, err
+		}
+		log.Printf("    ✓ Found portfolio marker in %s", source)
+		return source, metadata, nil
 	}
 
-	if hasEmojiMarker {
-		log.Printf("    ✓ Found 🎨 emoji marker in README")
-		return true, PortfolioMetadata{}, nil
+	if strings.Contains(readme, legacyEmojiMarker) {
+		if g.legacyEmoji {
+			log.Printf("    ✓ Found 🎨 emoji marker in README (legacy)")
+			return "🎨 README marker (legacy)", PortfolioMetadata{}, nil
+		}
+		log.Printf("    Ignoring 🎨 in README (legacy emoji marker disabled)")
 	}
 
 	log.Printf("    No portfolio markers found")
-	return false, PortfolioMetadata{}, nil
+	return "", PortfolioMetadata{}, nil
 }
 
-// fetchFileContent fetches a file from a repository
-func (g *GitHubScraper) fetchFileContent(repoName, filePath string) (string, error) {
-	url := fmt.Sprintf("%s/repos/%s/%s/contents/%s", githubAPIBase, g.username, repoName, filePath)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+// reportInvalidPortfolio logs invalid portfolio metadata and records
+// validation errors for the summary after Scrape
+func (g *GitHubScraper) reportInvalidPortfolio(c *repoCandidate, source string, err error) {
+	log.Printf("    Warning: Invalid %s in %s: %v", source, c.Repo.Name, err)
+	var validationErr *PortfolioValidationError
+	if errors.As(err, &validationErr) {
+		validationErr.Repo = g.ownerOf(c.Repo) + "/" + c.Repo.Name
+		g.recordPortfolioError(validationErr)
 	}
+}
 
-	if g.token != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", g.token))
-	}
-	req.Header.Set("Accept", "application/vnd.github.v3.raw")
+// fetchFileContent fetches a file from a repository
+func (g *GitHubScraper) fetchFileContent(ctx context.Context, owner, repoName, filePath string) (string, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/contents/%s", g.apiBase, owner, repoName, filePath)
 
-	resp, err := g.client.Do(req)
+	resp, err := g.get(ctx, url, "application/vnd.github.v3.raw")
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch file: %w", err)
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			log.Printf("Warning: failed to close response body: %v", closeErr)
-		}
-	}()
 
 	if resp.StatusCode == http.StatusNotFound {
 		return "", fmt.Errorf("file not found")
@@ -358,67 +1210,210 @@ func (g *GitHubScraper) fetchFileContent(repoName, filePath string) (string, err
 		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	return string(resp.Body), nil
+}
+
+// portfolioContent returns the name and content of the first marker file
+// found in a candidate (see portfolioFiles)
+func (g *GitHubScraper) portfolioContent(ctx context.Context, c *repoCandidate) (string, string, error) {
+	if c.Prefetched {
+		if c.Portfolio == nil {
+			return "", "", fmt.Errorf("file not found")
+		}
+		return c.PortfolioPath, *c.Portfolio, nil
+	}
+
+	files, err := g.listRootFiles(ctx, c)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return "", "", err
 	}
+	for _, name := range portfolioFiles {
+		if files[name] {
+			content, err := g.fetchFileContent(ctx, g.ownerOf(c.Repo), c.Repo.Name, name)
+			return name, content, err
+		}
+	}
+	return "", "", fmt.Errorf("file not found")
+}
+
+// readmeContent returns the README of a candidate. REST lookups go through
+// the per-run memo, so every README is fetched at most once per Scrape.
+func (g *GitHubScraper) readmeContent(ctx context.Context, c *repoCandidate) (string, error) {
+	if c.README != nil {
+		return *c.README, nil
+	}
+	if c.Prefetched {
+		return "", fmt.Errorf("README not found")
+	}
+
+	readme, name, err := g.readmes.load(g.ownerOf(c.Repo)+"/"+c.Repo.Name, func() (string, string, error) {
+		return g.fetchREADME(ctx, c)
+	})
+	if err != nil {
+		return "", err
+	}
+	c.README, c.READMEPath = &readme, name
+	return readme, nil
+}
 
-	return string(body), nil
+// fetchREADME fetches the first README variation present in the repository
+// root and returns its content and name
+func (g *GitHubScraper) fetchREADME(ctx context.Context, c *repoCandidate) (string, string, error) {
+	files, err := g.listRootFiles(ctx, c)
+	if err != nil {
+		return "", "", err
+	}
+	for _, name := range readmeVariations {
+		if !files[name] {
+			continue
+		}
+		readme, err := g.fetchFileContent(ctx, g.ownerOf(c.Repo), c.Repo.Name, name)
+		if err != nil {
+			return "", "", err
+		}
+		return readme, name, nil
+	}
+	return "", "", fmt.Errorf("README not found")
 }
 
-// fetchREADME fetches the README file from a repository
-func (g *GitHubScraper) fetchREADME(repoName string) (string, error) {
-	// Try different README filename variations
-	variations := []string{"README.md", "README.MD", "readme.md", "Readme.md", "README"}
+// listRootFiles returns the names of the files in the repository root. One
+// directory listing replaces probing every marker and README variation.
+func (g *GitHubScraper) listRootFiles(ctx context.Context, c *repoCandidate) (map[string]bool, error) {
+	if c.rootFiles != nil || c.rootErr != nil {
+		return c.rootFiles, c.rootErr
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/contents", g.apiBase, g.ownerOf(c.Repo), c.Repo.Name)
+	resp, err := g.get(ctx, url, "application/vnd.github.v3+json")
+	if err != nil {
+		c.rootErr = fmt.Errorf("failed to list repository files: %w", err)
+		return nil, c.rootErr
+	}
 
-	for _, filename := range variations {
-		content, err := g.fetchFileContent(repoName, filename)
-		if err == nil {
-			return content, nil
+	c.rootFiles = make(map[string]bool)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var entries []struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(resp.Body, &entries); err != nil {
+			c.rootFiles = nil
+			c.rootErr = fmt.Errorf("failed to decode repository files: %w", err)
+			return nil, c.rootErr
 		}
+		for _, entry := range entries {
+			if entry.Type == "file" || entry.Type == "symlink" {
+				c.rootFiles[entry.Name] = true
+			}
+		}
+	case http.StatusNotFound:
+		// Empty repositories have no contents
+	default:
+		c.rootFiles = nil
+		c.rootErr = fmt.Errorf("failed to list repository files: unexpected status code: %d", resp.StatusCode)
+		return nil, c.rootErr
 	}
 
-	return "", fmt.Errorf("README not found")
+	return c.rootFiles, nil
 }
 
 // extractImagesFromREADME extracts image URLs from README markdown
-func (g *GitHubScraper) extractImagesFromREADME(repoName string) ([]string, error) {
-	readme, err := g.fetchREADME(repoName)
+func (g *GitHubScraper) extractImagesFromREADME(ctx context.Context, c *repoCandidate) ([]string, error) {
+	readme, err := g.readmeContent(ctx, c)
 	if err != nil {
 		return nil, err
 	}
 
-	// Regex to match markdown images: ![alt](url)
-	imgRegex := regexp.MustCompile(`!\[.*?\]\((.*?)\)`)
-	matches := imgRegex.FindAllStringSubmatch(readme, -1)
-
+	baseDir := path.Dir(c.READMEPath)
 	images := make([]string, 0)
-	for _, match := range matches {
-		if len(match) > 1 {
-			imageURL := g.normalizeImageURL(match[1], repoName)
-			images = append(images, imageURL)
+	for _, ref := range findImageReferences(readme) {
+		if normalized := g.normalizeImageURL(ref, c.Repo, baseDir); normalized != "" {
+			images = append(images, normalized)
 		}
 	}
 
 	return images, nil
 }
 
-// normalizeImageURL converts relative image paths to absolute GitHub URLs
-func (g *GitHubScraper) normalizeImageURL(imageURL, repoName string) string {
+// findImageReferences returns markdown and HTML image sources in the order
+// they appear in the document
+func findImageReferences(markdown string) []string {
+	type match struct {
+		pos int
+		ref string
+	}
+
+	matches := make([]match, 0)
+	for _, re := range []*regexp.Regexp{markdownImageRegex, htmlImageRegex} {
+		for _, m := range re.FindAllStringSubmatchIndex(markdown, -1) {
+			// Use the first capture group that participated in the match
+			for i := 2; i+1 < len(m); i += 2 {
+				if m[i] != -1 {
+					matches = append(matches, match{pos: m[0], ref: markdown[m[i]:m[i+1]]})
+					break
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b match) int {
+		return cmp.Compare(a.pos, b.pos)
+	})
+
+	refs := make([]string, 0, len(matches))
+	for _, m := range matches {
+		refs = append(refs, m.ref)
+	}
+	return refs
+}
+
+// normalizeImageURL converts relative image paths to absolute GitHub URLs.
+// Relative paths are resolved against baseDir (the directory of the file
+// that referenced the image) on the repository's default branch. Inline
+// data: images and other schemes (mailto:, javascript:) cannot be mirrored
+// and yield "".
+func (g *GitHubScraper) normalizeImageURL(imageURL string, repo GitHubRepo, baseDir string) string {
 	// If already an absolute URL (http/https), return as-is
-	if strings.HasPrefix(imageURL, "http://") || strings.HasPrefix(imageURL, "https://") {
+	lower := strings.ToLower(imageURL)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
 		return imageURL
 	}
 
-	// Remove leading ./ if present
-	cleanPath := strings.TrimPrefix(imageURL, "./")
+	// Protocol-relative URLs point to another host
+	if strings.HasPrefix(imageURL, "//") {
+		return "https:" + imageURL
+	}
+
+	// A colon before the first slash starts a scheme, not a path
+	if imageURL == "" || strings.Contains(strings.SplitN(imageURL, "/", 2)[0], ":") {
+		return ""
+	}
 
-	// Remove leading / if present (for absolute paths within repo)
-	cleanPath = strings.TrimPrefix(cleanPath, "/")
+	branch := repo.DefaultBranch
+	if branch == "" {
+		branch = "main"
+	}
 
 	// Convert to raw GitHub URL
-	return fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/main/%s",
-		g.username, repoName, cleanPath)
+	return fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s/%s",
+		g.ownerOf(repo), repo.Name, branch, resolveRepoPath(baseDir, imageURL))
+}
+
+// resolveRepoPath resolves a relative reference against a directory inside
+// the repository. Leading "/" refers to the repository root, and ".."
+// segments cannot escape it. Query strings and fragments are dropped.
+func resolveRepoPath(baseDir, ref string) string {
+	if i := strings.IndexAny(ref, "?#"); i != -1 {
+		ref = ref[:i]
+	}
+
+	if !strings.HasPrefix(ref, "/") {
+		ref = path.Join(baseDir, ref)
+	}
+
+	// Cleaning a rooted path removes any ".." that would climb above "/"
+	return strings.TrimPrefix(path.Clean("/"+ref), "/")
 }
 
 // deduplicateStrings removes duplicate strings from a slice
@@ -485,9 +1480,13 @@ func isBadgeURL(imageURL string) bool {
 	return false
 }
 
-// sortProjects sorts projects by priority descending, then stars descending, then name ascending
+// sortProjects sorts projects by order group ascending, then priority descending,
+// then stars descending, then name ascending
 func sortProjects(projects []Project) {
 	slices.SortStableFunc(projects, func(a, b Project) int {
+		if c := cmp.Compare(a.OrderGroup, b.OrderGroup); c != 0 {
+			return c
+		}
 		if c := cmp.Compare(b.Priority, a.Priority); c != 0 {
 			return c
 		}
@@ -498,6 +1497,39 @@ func sortProjects(projects []Project) {
 	})
 }
 
+// GroupProjects groups sorted projects by category. Groups are ordered by
+// their first project, so order_group and priority also order the
+// categories; uncategorized projects are collected in a trailing group.
+func GroupProjects(projects []Project) []ProjectGroup {
+	groups := make([]ProjectGroup, 0)
+	index := make(map[string]int)
+	var uncategorized []Project
+
+	for _, p := range projects {
+		if p.Category == "" {
+			uncategorized = append(uncategorized, p)
+			continue
+		}
+		key := strings.ToLower(p.Category)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, ProjectGroup{Category: p.Category, Projects: make([]Project, 0)})
+		}
+		groups[i].Projects = append(groups[i].Projects, p)
+	}
+
+	if len(uncategorized) > 0 {
+		if i, ok := index[strings.ToLower(UncategorizedCategory)]; ok {
+			groups[i].Projects = append(groups[i].Projects, uncategorized...)
+		} else {
+			groups = append(groups, ProjectGroup{Category: UncategorizedCategory, Projects: uncategorized})
+		}
+	}
+	return groups
+}
+
 // separateImagesAndBadges splits images into regular images and badges
 func separateImagesAndBadges(images []string) (regularImages []string, badges []string) {
 	regularImages = make([]string, 0)
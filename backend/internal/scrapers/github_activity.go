@@ -0,0 +1,447 @@
+package scrapers
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mrcodeeu/homepage/internal/storage"
+)
+
+const (
+	cacheKeyGitHubActivity = "github_activity"
+
+	// defaultActivityEvents is the default number of recent events kept
+	defaultActivityEvents = 30
+	// activityEventPages bounds the event pages fetched; GitHub only keeps
+	// the latest 300 public events (3 pages of 100) of the last 90 days
+	activityEventPages = 3
+)
+
+// graphqlContributionsQuery fetches the contribution calendar and the commit
+// counts per repository of a user for the given time window (at most a year)
+const graphqlContributionsQuery = `query($login: String!, $from: DateTime!, $to: DateTime!) {
+  user(login: $login) {
+    contributionsCollection(from: $from, to: $to) {
+      contributionCalendar {
+        totalContributions
+        weeks { contributionDays { date contributionCount contributionLevel } }
+      }
+      commitContributionsByRepository(maxRepositories: 100) {
+        repository { nameWithOwner url isPrivate }
+        contributions { totalCount }
+      }
+    }
+  }
+}`
+
+// contributionLevels maps GitHub's calendar levels to 0 (none) to 4 (busiest)
+var contributionLevels = map[string]int{
+	"NONE":            0,
+	"FIRST_QUARTILE":  1,
+	"SECOND_QUARTILE": 2,
+	"THIRD_QUARTILE":  3,
+	"FOURTH_QUARTILE": 4,
+}
+
+// GitHubActivity is the recent public activity of a GitHub user
+type GitHubActivity struct {
+	Username string `json:"username"`
+	From     string `json:"from"` // first day of the calendar window, YYYY-MM-DD
+	To       string `json:"to"`   // last day of the calendar window, YYYY-MM-DD
+	// Calendar is only available with a token since it needs the GraphQL API
+	Calendar    *ContributionCalendar `json:"calendar,omitempty"`
+	Events      []ActivityEvent       `json:"events"`
+	RepoCommits []RepoCommitCount     `json:"repo_commits"` // most commits first
+}
+
+// ContributionCalendar is the daily contribution count shown on a GitHub profile
+type ContributionCalendar struct {
+	Total int               `json:"total"`
+	Days  []ContributionDay `json:"days"` // oldest first
+}
+
+// ContributionDay is a single day of the contribution calendar
+type ContributionDay struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Count int    `json:"count"`
+	Level int    `json:"level"` // 0 (none) to 4 (busiest quartile)
+}
+
+// Kinds of activity events
+const (
+	ActivityPush        = "push"
+	ActivityPullRequest = "pull_request"
+	ActivityRelease     = "release"
+)
+
+// ActivityEvent is a push, pull request or release of the user
+type ActivityEvent struct {
+	Type   string `json:"type"` // ActivityPush, ActivityPullRequest or ActivityRelease
+	Repo   string `json:"repo"` // owner/name
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	Ref    string `json:"ref,omitempty"`    // branch of a push, tag of a release
+	Action string `json:"action,omitempty"` // pull requests: opened, merged or closed
+	// Commits is the number of commits of a push
+	Commits   int       `json:"commits,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RepoCommitCount is the number of commits made to a repository in the
+// calendar window
+type RepoCommitCount struct {
+	Repo    string `json:"repo"` // owner/name
+	URL     string `json:"url"`
+	Commits int    `json:"commits"`
+}
+
+// GitHubActivityScraper implements the Scraper interface for the
+// contribution activity of a GitHub user. It shares the HTTP handling (rate
+// limits, retries, conditional requests) of GitHubScraper.
+type GitHubActivityScraper struct {
+	api       *GitHubScraper
+	maxEvents int
+	now       func() time.Time
+}
+
+// NewGitHubActivityScraper creates a new GitHub activity scraper
+func NewGitHubActivityScraper(username, token string, cache storage.Cache) *GitHubActivityScraper {
+	return &GitHubActivityScraper{
+		api:       NewGitHubScraper(username, token, cache),
+		maxEvents: defaultActivityEvents,
+		now:       time.Now,
+	}
+}
+
+// SetMaxEvents sets how many recent events are kept; values below 1
+// restore the default
+func (a *GitHubActivityScraper) SetMaxEvents(count int) {
+	if count < 1 {
+		count = defaultActivityEvents
+	}
+	a.maxEvents = count
+}
+
+// Name returns the scraper name
+func (a *GitHubActivityScraper) Name() string {
+	return "github_activity"
+}
+
+// GetCached returns cached activity or scrapes if needed
+func (a *GitHubActivityScraper) GetCached() (any, error) {
+	cached, err := a.api.cache.Get(cacheKeyGitHubActivity)
+	if err != nil {
+		return nil, fmt.Errorf("cache error: %w", err)
+	}
+
+	if cached != nil {
+		var activity GitHubActivity
+		if err := json.Unmarshal(cached, &activity); err != nil {
+			// Invalid cache, scrape fresh data
+			return a.Refresh()
+		}
+		return &activity, nil
+	}
+
+	return a.Refresh()
+}
+
+// Scrape fetches fresh activity from GitHub
+func (a *GitHubActivityScraper) Scrape() (any, error) {
+	return a.ScrapeContext(context.Background())
+}
+
+// ScrapeContext fetches the contribution calendar, the commit counts per
+// repository of the last year and the most recent public events. Without a
+// token only the events are fetched.
+func (a *GitHubActivityScraper) ScrapeContext(ctx context.Context) (any, error) {
+	username := a.api.username
+	to := a.now().UTC()
+	// GitHub rejects windows longer than a year, so the window starts the
+	// day after the same date one year ago
+	from := time.Date(to.Year()-1, to.Month(), to.Day()+1, 0, 0, 0, 0, time.UTC)
+
+	activity := &GitHubActivity{
+		Username:    username,
+		From:        from.Format(time.DateOnly),
+		To:          to.Format(time.DateOnly),
+		Events:      make([]ActivityEvent, 0),
+		RepoCommits: make([]RepoCommitCount, 0),
+	}
+
+	if a.api.token != "" {
+		log.Printf("Fetching contribution calendar for %s (%s to %s)", username, activity.From, activity.To)
+		calendar, commits, err := a.fetchContributions(ctx, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch contributions: %w", err)
+		}
+		activity.Calendar = calendar
+		activity.RepoCommits = commits
+	} else {
+		log.Println("Warning: no GitHub token set, skipping contribution calendar and commit counts")
+	}
+
+	events, err := a.fetchEvents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch events: %w", err)
+	}
+	activity.Events = events
+
+	contributions := 0
+	if activity.Calendar != nil {
+		contributions = activity.Calendar.Total
+	}
+	log.Printf("GitHub activity: %d contributions, %d repositories with commits, %d recent events",
+		contributions, len(activity.RepoCommits), len(activity.Events))
+	return activity, nil
+}
+
+// Refresh forces a fresh scrape and updates cache
+func (a *GitHubActivityScraper) Refresh() (any, error) {
+	activity, err := a.Scrape()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(activity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal activity: %w", err)
+	}
+
+	if err := a.api.cache.Set(cacheKeyGitHubActivity, data, a.api.cacheTTL); err != nil {
+		// Log error but don't fail - we still have the data
+		log.Printf("Warning: failed to update cache: %v", err)
+	}
+
+	return activity, nil
+}
+
+// graphqlContributions is the data of graphqlContributionsQuery
+type graphqlContributions struct {
+	User *struct {
+		ContributionsCollection struct {
+			ContributionCalendar struct {
+				TotalContributions int `json:"totalContributions"`
+				Weeks              []struct {
+					ContributionDays []struct {
+						Date              string `json:"date"`
+						ContributionCount int    `json:"contributionCount"`
+						ContributionLevel string `json:"contributionLevel"`
+					} `json:"contributionDays"`
+				} `json:"weeks"`
+			} `json:"contributionCalendar"`
+			CommitContributionsByRepository []struct {
+				Repository struct {
+					NameWithOwner string `json:"nameWithOwner"`
+					URL           string `json:"url"`
+					IsPrivate     bool   `json:"isPrivate"`
+				} `json:"repository"`
+				Contributions struct {
+					TotalCount int `json:"totalCount"`
+				} `json:"contributions"`
+			} `json:"commitContributionsByRepository"`
+		} `json:"contributionsCollection"`
+	} `json:"user"`
+}
+
+// fetchContributions fetches the contribution calendar and the commit counts
+// per public repository between from and to
+func (a *GitHubActivityScraper) fetchContributions(ctx context.Context, from, to time.Time) (*ContributionCalendar, []RepoCommitCount, error) {
+	var data graphqlContributions
+	if err := a.api.queryGraphQL(ctx, graphqlContributionsQuery, map[string]any{
+		"login": a.api.username,
+		"from":  from.Format(time.RFC3339),
+		"to":    to.Format(time.RFC3339),
+	}, &data); err != nil {
+		return nil, nil, err
+	}
+	if data.User == nil {
+		return nil, nil, fmt.Errorf("user %s not found", a.api.username)
+	}
+	collection := data.User.ContributionsCollection
+
+	calendar := &ContributionCalendar{
+		Total: collection.ContributionCalendar.TotalContributions,
+		Days:  make([]ContributionDay, 0, 371),
+	}
+	for _, week := range collection.ContributionCalendar.Weeks {
+		for _, day := range week.ContributionDays {
+			calendar.Days = append(calendar.Days, ContributionDay{
+				Date:  day.Date,
+				Count: day.ContributionCount,
+				Level: contributionLevels[day.ContributionLevel],
+			})
+		}
+	}
+
+	commits := make([]RepoCommitCount, 0, len(collection.CommitContributionsByRepository))
+	private := 0
+	for _, entry := range collection.CommitContributionsByRepository {
+		// Tokens with the repo scope also see private repositories, which
+		// must not end up on the public site
+		if entry.Repository.IsPrivate {
+			private++
+			continue
+		}
+		commits = append(commits, RepoCommitCount{
+			Repo:    entry.Repository.NameWithOwner,
+			URL:     entry.Repository.URL,
+			Commits: entry.Contributions.TotalCount,
+		})
+	}
+	if private > 0 {
+		log.Printf("Omitted commit counts of %d private repositories", private)
+	}
+	slices.SortFunc(commits, func(x, y RepoCommitCount) int {
+		if c := cmp.Compare(y.Commits, x.Commits); c != 0 {
+			return c
+		}
+		return cmp.Compare(x.Repo, y.Repo)
+	})
+
+	return calendar, commits, nil
+}
+
+// githubEvent is an entry of the public events API
+type githubEvent struct {
+	Type string `json:"type"`
+	Repo struct {
+		Name string `json:"name"`
+	} `json:"repo"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// pushPayload is the payload of a PushEvent. Newer events may omit the
+// commit list and size.
+type pushPayload struct {
+	Ref     string `json:"ref"`
+	Head    string `json:"head"`
+	Size    int    `json:"size"`
+	Commits []struct {
+		Message string `json:"message"`
+	} `json:"commits"`
+}
+
+// pullRequestPayload is the payload of a PullRequestEvent
+type pullRequestPayload struct {
+	Action      string `json:"action"`
+	PullRequest struct {
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+		Merged  bool   `json:"merged"`
+	} `json:"pull_request"`
+}
+
+// releasePayload is the payload of a ReleaseEvent
+type releasePayload struct {
+	Action  string `json:"action"`
+	Release struct {
+		Name    string `json:"name"`
+		TagName string `json:"tag_name"`
+		HTMLURL string `json:"html_url"`
+	} `json:"release"`
+}
+
+// fetchEvents fetches the most recent public pushes, pull requests and
+// releases of the user, newest first
+func (a *GitHubActivityScraper) fetchEvents(ctx context.Context) ([]ActivityEvent, error) {
+	events := make([]ActivityEvent, 0, a.maxEvents)
+	next := fmt.Sprintf("%s/users/%s/events/public?per_page=100", a.api.apiBase, url.PathEscape(a.api.username))
+
+	for page := 0; next != "" && page < activityEventPages && len(events) < a.maxEvents; page++ {
+		resp, err := a.api.get(ctx, next, "application/vnd.github.v3+json")
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(resp.Body))
+		}
+
+		var raw []githubEvent
+		if err := json.Unmarshal(resp.Body, &raw); err != nil {
+			return nil, fmt.Errorf("failed to decode events: %w", err)
+		}
+		for _, event := range raw {
+			if activityEvent, ok := convertEvent(event); ok && len(events) < a.maxEvents {
+				events = append(events, activityEvent)
+			}
+		}
+		next = parseNextLink(resp.Header.Get("Link"))
+	}
+
+	return events, nil
+}
+
+// convertEvent converts a public event into an ActivityEvent. Other event
+// types, pull request reviews and unpublished releases are dropped.
+func convertEvent(event githubEvent) (ActivityEvent, bool) {
+	activity := ActivityEvent{
+		Repo:      event.Repo.Name,
+		CreatedAt: event.CreatedAt,
+	}
+	repoURL := "https://github.com/" + event.Repo.Name
+
+	switch event.Type {
+	case "PushEvent":
+		var payload pushPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return ActivityEvent{}, false
+		}
+		activity.Type = ActivityPush
+		activity.Ref = strings.TrimPrefix(payload.Ref, "refs/heads/")
+		activity.Commits = max(payload.Size, len(payload.Commits))
+		activity.URL = repoURL + "/tree/" + activity.Ref
+		if payload.Head != "" {
+			activity.URL = repoURL + "/commit/" + payload.Head
+		}
+		activity.Title = activity.Ref
+		if len(payload.Commits) > 0 {
+			message := payload.Commits[len(payload.Commits)-1].Message
+			title, _, _ := strings.Cut(message, "\n")
+			activity.Title = strings.TrimSpace(title)
+		}
+
+	case "PullRequestEvent":
+		var payload pullRequestPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return ActivityEvent{}, false
+		}
+		switch {
+		case payload.Action == "opened":
+			activity.Action = "opened"
+		case payload.Action == "closed" && payload.PullRequest.Merged:
+			activity.Action = "merged"
+		case payload.Action == "closed":
+			activity.Action = "closed"
+		default:
+			return ActivityEvent{}, false
+		}
+		activity.Type = ActivityPullRequest
+		activity.Title = payload.PullRequest.Title
+		activity.URL = payload.PullRequest.HTMLURL
+
+	case "ReleaseEvent":
+		var payload releasePayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil || payload.Action != "published" {
+			return ActivityEvent{}, false
+		}
+		activity.Type = ActivityRelease
+		activity.Ref = payload.Release.TagName
+		activity.Title = cmp.Or(payload.Release.Name, payload.Release.TagName)
+		activity.URL = payload.Release.HTMLURL
+
+	default:
+		return ActivityEvent{}, false
+	}
+
+	return activity, true
+}
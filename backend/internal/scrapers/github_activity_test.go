@@ -0,0 +1,160 @@
+package scrapers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const activityEventsPage1 = `[
+  {"type": "PushEvent", "repo": {"name": "testuser/tool"}, "created_at": "2026-10-15T10:00:00Z",
+   "payload": {"ref": "refs/heads/main", "head": "abc123", "size": 2,
+     "commits": [{"message": "First"}, {"message": "Add parser\n\nLonger body"}]}},
+  {"type": "WatchEvent", "repo": {"name": "other/repo"}, "created_at": "2026-10-14T10:00:00Z", "payload": {}},
+  {"type": "PullRequestEvent", "repo": {"name": "other/lib"}, "created_at": "2026-10-13T10:00:00Z",
+   "payload": {"action": "closed", "pull_request": {"title": "Fix typo", "html_url": "https://github.com/other/lib/pull/7", "merged": true}}}
+]`
+
+const activityEventsPage2 = `[
+  {"type": "PullRequestEvent", "repo": {"name": "other/lib"}, "created_at": "2026-10-12T10:00:00Z",
+   "payload": {"action": "labeled", "pull_request": {"title": "Fix typo"}}},
+  {"type": "ReleaseEvent", "repo": {"name": "testuser/tool"}, "created_at": "2026-10-11T10:00:00Z",
+   "payload": {"action": "published", "release": {"tag_name": "v1.2.0", "html_url": "https://github.com/testuser/tool/releases/tag/v1.2.0"}}},
+  {"type": "PushEvent", "repo": {"name": "testuser/tool"}, "created_at": "2026-10-10T10:00:00Z",
+   "payload": {"ref": "refs/heads/dev", "head": "def456"}}
+]`
+
+const activityContributions = `{"data": {"user": {"contributionsCollection": {
+  "contributionCalendar": {"totalContributions": 5, "weeks": [
+    {"contributionDays": [
+      {"date": "2025-10-17", "contributionCount": 0, "contributionLevel": "NONE"},
+      {"date": "2025-10-18", "contributionCount": 5, "contributionLevel": "FOURTH_QUARTILE"}
+    ]}
+  ]},
+  "commitContributionsByRepository": [
+    {"repository": {"nameWithOwner": "testuser/secret", "url": "https://github.com/testuser/secret", "isPrivate": true}, "contributions": {"totalCount": 50}},
+    {"repository": {"nameWithOwner": "testuser/b", "url": "https://github.com/testuser/b", "isPrivate": false}, "contributions": {"totalCount": 3}},
+    {"repository": {"nameWithOwner": "testuser/a", "url": "https://github.com/testuser/a", "isPrivate": false}, "contributions": {"totalCount": 3}},
+    {"repository": {"nameWithOwner": "testuser/tool", "url": "https://github.com/testuser/tool", "isPrivate": false}, "contributions": {"totalCount": 9}}
+  ]
+}}}}`
+
+// newActivityTestServer serves two pages of public events and the
+// contributions GraphQL query. The GraphQL variables are stored in variables.
+func newActivityTestServer(t *testing.T, variables map[string]any) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch {
+		case r.URL.Path == "/users/testuser/events/public" && r.URL.Query().Get("page") == "":
+			w.Header().Set("Link", `<`+server.URL+`/users/testuser/events/public?per_page=100&page=2>; rel="next"`)
+			body = activityEventsPage1
+		case r.URL.Path == "/users/testuser/events/public":
+			body = activityEventsPage2
+		case r.URL.Path == "/graphql":
+			var request graphqlRequest
+			data, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(data, &request); err != nil {
+				t.Errorf("Invalid GraphQL request: %v", err)
+			}
+			if !strings.Contains(request.Query, "contributionCalendar") {
+				t.Errorf("Unexpected GraphQL query: %s", request.Query)
+			}
+			for key, value := range request.Variables {
+				variables[key] = value
+			}
+			body = activityContributions
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Errorf("Failed to write response: %v", err)
+		}
+	}))
+	return server
+}
+
+func newTestActivityScraper(server *httptest.Server, token string) *GitHubActivityScraper {
+	scraper := NewGitHubActivityScraper("testuser", token, newMockCache())
+	scraper.api.client = server.Client()
+	scraper.api.apiBase = server.URL
+	scraper.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+	return scraper
+}
+
+func TestGitHubActivityScraper_Scrape(t *testing.T) {
+	variables := make(map[string]any)
+	server := newActivityTestServer(t, variables)
+	defer server.Close()
+
+	result, err := newTestActivityScraper(server, "token").Scrape()
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	activity := result.(*GitHubActivity)
+
+	if activity.From != "2025-10-17" || activity.To != "2026-10-16" {
+		t.Errorf("Expected a window from 2025-10-17 to 2026-10-16, got %s to %s", activity.From, activity.To)
+	}
+	if variables["login"] != "testuser" || variables["from"] != "2025-10-17T00:00:00Z" {
+		t.Errorf("Unexpected GraphQL variables: %v", variables)
+	}
+
+	expectedDays := []ContributionDay{{Date: "2025-10-17", Count: 0, Level: 0}, {Date: "2025-10-18", Count: 5, Level: 4}}
+	if activity.Calendar == nil || activity.Calendar.Total != 5 || !reflect.DeepEqual(activity.Calendar.Days, expectedDays) {
+		t.Errorf("Unexpected calendar: %+v", activity.Calendar)
+	}
+
+	repos := make([]string, 0, len(activity.RepoCommits))
+	for _, count := range activity.RepoCommits {
+		repos = append(repos, count.Repo)
+	}
+	if expected := []string{"testuser/tool", "testuser/a", "testuser/b"}; !reflect.DeepEqual(repos, expected) {
+		t.Errorf("Expected public repositories by commit count %v, got %v", expected, repos)
+	}
+
+	if len(activity.Events) != 4 {
+		t.Fatalf("Expected 4 events across both pages, got %+v", activity.Events)
+	}
+	push := activity.Events[0]
+	if push.Type != ActivityPush || push.Title != "Add parser" || push.Commits != 2 || push.Ref != "main" ||
+		push.URL != "https://github.com/testuser/tool/commit/abc123" {
+		t.Errorf("Unexpected push event: %+v", push)
+	}
+	if pr := activity.Events[1]; pr.Type != ActivityPullRequest || pr.Action != "merged" || pr.Title != "Fix typo" {
+		t.Errorf("Unexpected pull request event: %+v", pr)
+	}
+	if release := activity.Events[2]; release.Type != ActivityRelease || release.Title != "v1.2.0" || release.Ref != "v1.2.0" {
+		t.Errorf("Unexpected release event: %+v", release)
+	}
+	if push := activity.Events[3]; push.Title != "dev" || push.Commits != 0 {
+		t.Errorf("Expected a push without commit list to fall back to the branch, got %+v", push)
+	}
+}
+
+func TestGitHubActivityScraper_WithoutToken(t *testing.T) {
+	variables := make(map[string]any)
+	server := newActivityTestServer(t, variables)
+	defer server.Close()
+
+	scraper := newTestActivityScraper(server, "")
+	scraper.SetMaxEvents(2)
+	result, err := scraper.Scrape()
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	activity := result.(*GitHubActivity)
+
+	if activity.Calendar != nil || len(activity.RepoCommits) != 0 || len(variables) != 0 {
+		t.Errorf("Expected no GraphQL query without a token, got %+v", activity)
+	}
+	if len(activity.Events) != 2 {
+		t.Errorf("Expected the event limit to apply, got %d events", len(activity.Events))
+	}
+}
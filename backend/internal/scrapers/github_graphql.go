@@ -0,0 +1,373 @@
+package scrapers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// graphqlPageSize is kept small because every node carries blob texts
+const graphqlPageSize = 25
+
+// graphqlRepositoryFields selects everything the portfolio pipeline needs
+// from a repository, so no per-repository REST calls are required
+var graphqlRepositoryFields = buildRepositoryFields()
+
+// graphqlRepositoriesQuery lists the repositories owned by a user or organization
+var graphqlRepositoriesQuery = `query($login: String!, $cursor: String, $pageSize: Int!) {
+  repositoryOwner(login: $login) {
+    repositories(first: $pageSize, after: $cursor, ownerAffiliations: [OWNER], orderBy: {field: NAME, direction: ASC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {` + graphqlRepositoryFields + `
+      }
+    }
+  }
+}`
+
+// graphqlRepositoryQuery fetches a single repository by owner and name
+var graphqlRepositoryQuery = `query($owner: String!, $name: String!) {
+  repository(owner: $owner, name: $name) {` + graphqlRepositoryFields + `
+  }
+}`
+
+// buildRepositoryFields assembles the repository selection with one aliased
+// object lookup per marker file and README filename variation
+func buildRepositoryFields() string {
+	var blobs strings.Builder
+	for i, name := range portfolioFiles {
+		fmt.Fprintf(&blobs, "\n          portfolio%d: object(expression: \"HEAD:%s\") { ... on Blob { text } }", i, name)
+	}
+	for i, name := range readmeVariations {
+		fmt.Fprintf(&blobs, "\n          readme%d: object(expression: \"HEAD:%s\") { ... on Blob { text } }", i, name)
+	}
+
+	return `
+          name
+          description
+          url
+          stargazerCount
+          isPrivate
+          isFork
+          isArchived
+          isTemplate
+          owner { login }
+          homepageUrl
+          forkCount
+          pushedAt
+          licenseInfo { spdxId }
+          issues(states: OPEN) { totalCount }
+          pullRequests(states: OPEN) { totalCount }
+          latestRelease { tagName name url publishedAt }
+          languages(first: 20, orderBy: {field: SIZE, direction: DESC}) { edges { size node { name } } }
+          primaryLanguage { name }
+          defaultBranchRef { name }
+          repositoryTopics(first: 20) { nodes { topic { name } } }` + blobs.String()
+}
+
+// graphqlRequest is the body of a GraphQL API call
+type graphqlRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+// graphqlBlob is a file looked up via object(expression:); nil when missing
+type graphqlBlob struct {
+	Text *string `json:"text"`
+}
+
+// graphqlRepository mirrors the repository node selected by the query.
+// README variations are decoded separately since their aliases are dynamic.
+type graphqlRepository struct {
+	Name           string  `json:"name"`
+	Description    *string `json:"description"`
+	URL            string  `json:"url"`
+	StargazerCount int     `json:"stargazerCount"`
+	IsPrivate      bool    `json:"isPrivate"`
+	IsFork         bool    `json:"isFork"`
+	IsArchived     bool    `json:"isArchived"`
+	IsTemplate     bool    `json:"isTemplate"`
+	Owner          struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	HomepageURL *string   `json:"homepageUrl"`
+	ForkCount   int       `json:"forkCount"`
+	PushedAt    time.Time `json:"pushedAt"`
+	LicenseInfo *struct {
+		SPDXID string `json:"spdxId"`
+	} `json:"licenseInfo"`
+	Issues struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"issues"`
+	PullRequests struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"pullRequests"`
+	LatestRelease *struct {
+		TagName     string    `json:"tagName"`
+		Name        *string   `json:"name"`
+		URL         string    `json:"url"`
+		PublishedAt time.Time `json:"publishedAt"`
+	} `json:"latestRelease"`
+	Languages struct {
+		Edges []struct {
+			Size int `json:"size"`
+			Node struct {
+				Name string `json:"name"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"languages"`
+	PrimaryLanguage *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+	DefaultBranchRef *struct {
+		Name string `json:"name"`
+	} `json:"defaultBranchRef"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		} `json:"nodes"`
+	} `json:"repositoryTopics"`
+}
+
+// graphqlResponse is the envelope of every GraphQL API response
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// graphqlRepositoriesData is the data of the repositories query
+type graphqlRepositoriesData struct {
+	RepositoryOwner *struct {
+		Repositories struct {
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []json.RawMessage `json:"nodes"`
+		} `json:"repositories"`
+	} `json:"repositoryOwner"`
+}
+
+// graphqlRepositoryData is the data of the single repository query
+type graphqlRepositoryData struct {
+	Repository json.RawMessage `json:"repository"`
+}
+
+// fetchSourceGraphQL lists the repositories of a single source via GraphQL
+func (g *GitHubScraper) fetchSourceGraphQL(ctx context.Context, source GitHubSource) ([]repoCandidate, error) {
+	if source.Kind != GitHubSourceRepo {
+		return g.fetchRepositoriesGraphQL(ctx, source.Owner)
+	}
+
+	var data graphqlRepositoryData
+	if err := g.queryGraphQL(ctx, graphqlRepositoryQuery, map[string]any{
+		"owner": source.Owner,
+		"name":  source.Repo,
+	}, &data); err != nil {
+		return nil, err
+	}
+	if len(data.Repository) == 0 || string(data.Repository) == "null" {
+		return nil, fmt.Errorf("repository %s not found", source)
+	}
+
+	candidate, err := decodeGraphQLRepository(data.Repository)
+	if err != nil {
+		return nil, err
+	}
+	return []repoCandidate{candidate}, nil
+}
+
+// fetchRepositoriesGraphQL lists all repositories of a user or organization
+// together with their .portfolio and README contents using the GraphQL API
+func (g *GitHubScraper) fetchRepositoriesGraphQL(ctx context.Context, login string) ([]repoCandidate, error) {
+	startRequests := g.RequestCount()
+	candidates := make([]repoCandidate, 0)
+
+	var cursor *string
+	pages := 0
+	for {
+		if pages >= githubMaxPages {
+			log.Printf("Warning: stopped after %d GraphQL pages (page limit reached)", pages)
+			break
+		}
+
+		var page graphqlRepositoriesData
+		if err := g.queryGraphQL(ctx, graphqlRepositoriesQuery, map[string]any{
+			"login":    login,
+			"cursor":   cursor,
+			"pageSize": graphqlPageSize,
+		}, &page); err != nil {
+			return nil, fmt.Errorf("page %d (after %d repositories): %w", pages+1, len(candidates), err)
+		}
+		pages++
+
+		owner := page.RepositoryOwner
+		if owner == nil {
+			return nil, fmt.Errorf("GitHub account %q not found", login)
+		}
+
+		for _, raw := range owner.Repositories.Nodes {
+			candidate, err := decodeGraphQLRepository(raw)
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, candidate)
+		}
+
+		if !owner.Repositories.PageInfo.HasNextPage {
+			break
+		}
+		next := owner.Repositories.PageInfo.EndCursor
+		cursor = &next
+	}
+
+	log.Printf("Fetched %d repositories across %d GraphQL page(s) using %d request(s)",
+		len(candidates), pages, g.RequestCount()-startRequests)
+	return candidates, nil
+}
+
+// queryGraphQL sends a query to the GitHub GraphQL endpoint and decodes the
+// response data into out
+func (g *GitHubScraper) queryGraphQL(ctx context.Context, query string, variables map[string]any, out any) error {
+	payload, err := json.Marshal(graphqlRequest{Query: query, Variables: variables})
+	if err != nil {
+		return fmt.Errorf("failed to marshal GraphQL request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", g.apiBase+"/graphql", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", g.token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query GraphQL API: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Printf("Warning: failed to close response body: %v", closeErr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GitHub GraphQL API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result graphqlResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode GraphQL response: %w", err)
+	}
+
+	if len(result.Errors) > 0 {
+		messages := make([]string, 0, len(result.Errors))
+		for _, e := range result.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("GraphQL errors: %s", strings.Join(messages, "; "))
+	}
+
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return fmt.Errorf("failed to decode GraphQL data: %w", err)
+	}
+	return nil
+}
+
+// decodeGraphQLRepository converts a repository node into a prefetched candidate
+func decodeGraphQLRepository(raw json.RawMessage) (repoCandidate, error) {
+	var node graphqlRepository
+	if err := json.Unmarshal(raw, &node); err != nil {
+		return repoCandidate{}, fmt.Errorf("failed to decode repository: %w", err)
+	}
+
+	repo := GitHubRepo{
+		Name:       node.Name,
+		HTMLURL:    node.URL,
+		StarCount:  node.StargazerCount,
+		Private:    node.IsPrivate,
+		Fork:       node.IsFork,
+		Archived:   node.IsArchived,
+		IsTemplate: node.IsTemplate,
+		Topics:     make([]string, 0, len(node.RepositoryTopics.Nodes)),
+
+		ForksCount: node.ForkCount,
+		// Match the REST API, where open_issues_count includes pull requests
+		OpenIssuesCount: node.Issues.TotalCount + node.PullRequests.TotalCount,
+		PushedAt:        node.PushedAt,
+	}
+	if node.HomepageURL != nil {
+		repo.Homepage = *node.HomepageURL
+	}
+	if node.LicenseInfo != nil {
+		repo.License = &githubLicense{SPDXID: node.LicenseInfo.SPDXID}
+	}
+	if node.Description != nil {
+		repo.Description = *node.Description
+	}
+	if node.PrimaryLanguage != nil {
+		repo.Language = node.PrimaryLanguage.Name
+	}
+	repo.Owner.Login = node.Owner.Login
+	if node.DefaultBranchRef != nil {
+		repo.DefaultBranch = node.DefaultBranchRef.Name
+	}
+	for _, topic := range node.RepositoryTopics.Nodes {
+		repo.Topics = append(repo.Topics, topic.Topic.Name)
+	}
+
+	candidate := repoCandidate{
+		Repo:       repo,
+		Prefetched: true,
+		Languages:  make(map[string]int, len(node.Languages.Edges)),
+	}
+	for _, edge := range node.Languages.Edges {
+		candidate.Languages[edge.Node.Name] = edge.Size
+	}
+	if release := node.LatestRelease; release != nil {
+		candidate.Release = &ProjectRelease{
+			Tag:         release.TagName,
+			URL:         release.URL,
+			PublishedAt: release.PublishedAt,
+		}
+		if release.Name != nil {
+			candidate.Release.Name = *release.Name
+		}
+	}
+
+	// Marker files and README variations come back under portfolio0..N and
+	// readme0..N in lookup order
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return repoCandidate{}, fmt.Errorf("failed to decode file blobs: %w", err)
+	}
+	candidate.Portfolio, candidate.PortfolioPath = firstBlob(fields, "portfolio", portfolioFiles)
+	candidate.README, candidate.READMEPath = firstBlob(fields, "readme", readmeVariations)
+
+	return candidate, nil
+}
+
+// firstBlob returns the text and name of the first existing file among the
+// aliased object lookups prefix0..prefixN
+func firstBlob(fields map[string]json.RawMessage, prefix string, names []string) (*string, string) {
+	for i, name := range names {
+		var blob *graphqlBlob
+		if err := json.Unmarshal(fields[fmt.Sprintf("%s%d", prefix, i)], &blob); err != nil {
+			continue
+		}
+		if blob != nil && blob.Text != nil {
+			return blob.Text, name
+		}
+	}
+	return nil, ""
+}
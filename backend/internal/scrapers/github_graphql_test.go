@@ -0,0 +1,160 @@
+package scrapers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGitHubScraper_ScrapeGraphQL(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/graphql" || r.Method != http.MethodPost {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+
+		var req graphqlRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode GraphQL request: %v", err)
+		}
+		if req.Variables["login"] != "testuser" {
+			t.Errorf("Expected login 'testuser', got %v", req.Variables["login"])
+		}
+
+		response := `{"data": {"repositoryOwner": {"repositories": {
+			"pageInfo": {"hasNextPage": false, "endCursor": "abc"},
+			"nodes": [
+				{
+					"name": "with-portfolio",
+					"description": "From GitHub",
+					"url": "https://github.com/testuser/with-portfolio",
+					"stargazerCount": 3,
+					"isPrivate": false,
+					"primaryLanguage": {"name": "Go"},
+					"defaultBranchRef": {"name": "master"},
+					"forkCount": 2,
+					"licenseInfo": {"spdxId": "MIT"},
+					"issues": {"totalCount": 4},
+					"pullRequests": {"totalCount": 1},
+					"latestRelease": {"tagName": "v0.3.0", "name": null, "url": "https://github.com/testuser/with-portfolio/releases/tag/v0.3.0", "publishedAt": "2026-01-05T12:00:00Z"},
+					"languages": {"edges": [{"size": 900, "node": {"name": "Go"}}, {"size": 100, "node": {"name": "Shell"}}]},
+					"repositoryTopics": {"nodes": [{"topic": {"name": "cli"}}]},
+					"portfolio0": {"text": "{\"description\": \"From .portfolio\", \"featured\": true}"},
+					"readme0": null,
+					"readme1": null,
+					"readme2": {"text": "# Title\n![shot](https://example.com/shot.png)"},
+					"readme3": null,
+					"readme4": null
+				},
+				{
+					"name": "readme-marker",
+					"description": null,
+					"url": "https://github.com/testuser/readme-marker",
+					"stargazerCount": 10,
+					"isPrivate": false,
+					"primaryLanguage": null,
+					"defaultBranchRef": {"name": "main"},
+					"repositoryTopics": {"nodes": []},
+					"portfolio0": null,
+					"readme0": {"text": "<!-- PORTFOLIO -->"}
+				},
+				{
+					"name": "unmarked",
+					"url": "https://github.com/testuser/unmarked",
+					"repositoryTopics": {"nodes": []},
+					"portfolio0": null,
+					"readme0": {"text": "nothing to see"}
+				}
+			]
+		}}}}`
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write([]byte(response)); err != nil {
+			t.Fatalf("Failed to write response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	scraper := NewGitHubScraper("testuser", "token", newMockCache())
+	scraper.client = server.Client()
+	scraper.apiBase = server.URL
+	if err := scraper.SetMode(GitHubModeGraphQL); err != nil {
+		t.Fatalf("SetMode failed: %v", err)
+	}
+
+	result, err := scraper.Scrape()
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+
+	projects, ok := result.([]Project)
+	if !ok {
+		t.Fatalf("Expected []Project, got %T", result)
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected a single GraphQL request, got %d", requests)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("Expected 2 portfolio projects, got %d", len(projects))
+	}
+
+	// Sorted by stars: readme-marker (10) before with-portfolio (3)
+	if projects[0].Name != "readme-marker" || projects[1].Name != "with-portfolio" {
+		t.Errorf("Unexpected project order: %s, %s", projects[0].Name, projects[1].Name)
+	}
+
+	p := projects[1]
+	if p.Description != "From .portfolio" || !p.Featured {
+		t.Errorf("Expected .portfolio metadata to apply, got %+v", p)
+	}
+	if p.Language != "Go" || len(p.Topics) != 1 || p.Topics[0] != "cli" {
+		t.Errorf("Expected language and topics from GraphQL, got %q %v", p.Language, p.Topics)
+	}
+	if len(p.Images) != 1 || p.Images[0] != "https://example.com/shot.png" {
+		t.Errorf("Expected README image from readme.md variation, got %v", p.Images)
+	}
+	if p.Forks != 2 || p.OpenIssues != 5 || p.License != "MIT" {
+		t.Errorf("Unexpected repository stats: forks=%d issues=%d license=%q", p.Forks, p.OpenIssues, p.License)
+	}
+	if p.LatestRelease == nil || p.LatestRelease.Tag != "v0.3.0" {
+		t.Errorf("Expected latest release v0.3.0, got %+v", p.LatestRelease)
+	}
+	if len(p.Languages) != 2 || p.Languages[0].Name != "Go" || p.Languages[0].Percentage != 90 {
+		t.Errorf("Unexpected languages: %+v", p.Languages)
+	}
+}
+
+func TestGitHubScraper_SetMode(t *testing.T) {
+	scraper := NewGitHubScraper("testuser", "token", newMockCache())
+
+	if scraper.mode != GitHubModeREST {
+		t.Errorf("Expected default mode %q, got %q", GitHubModeREST, scraper.mode)
+	}
+	if err := scraper.SetMode(GitHubModeGraphQL); err != nil {
+		t.Errorf("Expected graphql mode to be accepted: %v", err)
+	}
+	if err := scraper.SetMode("soap"); err == nil {
+		t.Error("Expected unknown mode to be rejected")
+	}
+	if scraper.mode != GitHubModeGraphQL {
+		t.Errorf("Expected mode to remain %q after invalid SetMode, got %q", GitHubModeGraphQL, scraper.mode)
+	}
+}
+
+func TestGraphQLRepositoriesQuery(t *testing.T) {
+	for i, name := range readmeVariations {
+		alias := "readme" + string(rune('0'+i))
+		if !strings.Contains(graphqlRepositoriesQuery, alias+`: object(expression: "HEAD:`+name+`")`) {
+			t.Errorf("Expected query to look up %s as %s", name, alias)
+		}
+	}
+	for i, name := range portfolioFiles {
+		alias := "portfolio" + string(rune('0'+i))
+		if !strings.Contains(graphqlRepositoriesQuery, alias+`: object(expression: "HEAD:`+name+`")`) {
+			t.Errorf("Expected query to look up %s as %s", name, alias)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package scrapers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// defaultGitHubWorkers is the default number of repositories checked and
+// enriched concurrently. Each worker issues its requests sequentially, so
+// this also bounds the number of parallel requests to the GitHub API.
+const defaultGitHubWorkers = 8
+
+// candidateResult is the outcome of checking a single repository
+type candidateResult struct {
+	project  *Project
+	decision RepoDecision
+}
+
+// processCandidates applies the inclusion rules to every candidate and
+// builds the projects of those carrying a marker, using a bounded pool of
+// workers. Results keep the order of candidates. Once ctx is cancelled no
+// further candidates are started.
+func (g *GitHubScraper) processCandidates(ctx context.Context, candidates []repoCandidate) []candidateResult {
+	results := make([]candidateResult, len(candidates))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(g.workers, len(candidates)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = g.processCandidate(ctx, &candidates[i], i, len(candidates))
+			}
+		}()
+	}
+
+feed:
+	for i := range candidates {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// processCandidate decides whether a single repository is published
+func (g *GitHubScraper) processCandidate(ctx context.Context, c *repoCandidate, i, total int) candidateResult {
+	log.Printf("[%d/%d] Checking repository: %s/%s", i+1, total, g.ownerOf(c.Repo), c.Repo.Name)
+
+	// Apply inclusion rules before any marker is fetched
+	if ok, reason := g.rules.evaluate(c.Repo, g.ownerOf(c.Repo)); !ok {
+		return candidateResult{decision: g.decide(c.Repo, false, reason)}
+	}
+
+	project, reason, err := g.buildProject(ctx, c)
+	if err != nil {
+		// Log error with context but continue with the other repositories
+		log.Printf("Warning: Failed to check portfolio marker for %s: %v", c.Repo.Name, err)
+		return candidateResult{decision: g.decide(c.Repo, false, fmt.Sprintf("marker check failed: %v", err))}
+	}
+	return candidateResult{project: project, decision: g.decide(c.Repo, project != nil, reason)}
+}
+
+// readmeMemo shares README lookups between the steps of one Scrape run.
+// Concurrent lookups of the same repository wait for a single fetch.
+type readmeMemo struct {
+	mu      sync.Mutex
+	entries map[string]*readmeEntry
+}
+
+// readmeEntry is a memoized README lookup; failed lookups are kept as well
+type readmeEntry struct {
+	once    sync.Once
+	content string
+	name    string
+	err     error
+}
+
+func newReadmeMemo() *readmeMemo {
+	return &readmeMemo{entries: make(map[string]*readmeEntry)}
+}
+
+// load returns the README memoized for key ("owner/name"), calling fetch on
+// the first lookup. A nil memo calls fetch every time.
+func (m *readmeMemo) load(key string, fetch func() (string, string, error)) (string, string, error) {
+	if m == nil {
+		return fetch()
+	}
+
+	m.mu.Lock()
+	entry, ok := m.entries[key]
+	if !ok {
+		entry = &readmeEntry{}
+		m.entries[key] = entry
+	}
+	m.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.content, entry.name, entry.err = fetch()
+	})
+	return entry.content, entry.name, entry.err
+}
+
+// sleepContext waits for d, returning early with the context's error when
+// ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
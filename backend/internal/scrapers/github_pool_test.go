@@ -0,0 +1,179 @@
+package scrapers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newPoolTestServer serves count repositories marked through their README.
+// Requests for lower-numbered repositories are answered more slowly, so
+// workers finish out of order.
+func newPoolTestServer(t *testing.T, count int, inFlight, maxInFlight *atomic.Int64, readmeFetches map[string]int, mu *sync.Mutex) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			peak := maxInFlight.Load()
+			if current <= peak || maxInFlight.CompareAndSwap(peak, current) {
+				break
+			}
+		}
+
+		var body string
+		switch {
+		case r.URL.Path == "/users/testuser/repos":
+			repos := make([]string, count)
+			for i := range repos {
+				repos[i] = fmt.Sprintf(`{"name": "repo-%02d", "owner": {"login": "testuser"}}`, i)
+			}
+			body = "[" + strings.Join(repos, ",") + "]"
+		case strings.HasSuffix(r.URL.Path, "/contents"):
+			body = `[{"name": "README.md", "type": "file"}]`
+		case strings.HasSuffix(r.URL.Path, "/contents/README.md"):
+			name := strings.Split(r.URL.Path, "/")[3]
+			mu.Lock()
+			readmeFetches[name]++
+			mu.Unlock()
+
+			var index int
+			if _, err := fmt.Sscanf(name, "repo-%d", &index); err == nil {
+				time.Sleep(time.Duration(count-index) * time.Millisecond)
+			}
+			body = "# Repo\n<!-- PORTFOLIO -->\n![shot](shot.png)\n"
+			if index%3 == 0 {
+				body = "# Unmarked\n"
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Errorf("Failed to write response: %v", err)
+		}
+	}))
+}
+
+func TestGitHubScraper_ConcurrentEnrichment(t *testing.T) {
+	const count = 12
+	var inFlight, maxInFlight atomic.Int64
+	var mu sync.Mutex
+	readmeFetches := make(map[string]int)
+	server := newPoolTestServer(t, count, &inFlight, &maxInFlight, readmeFetches, &mu)
+	defer server.Close()
+
+	scraper := NewGitHubScraper("testuser", "token", newMockCache())
+	scraper.client = server.Client()
+	scraper.apiBase = server.URL
+	scraper.SetConcurrency(3)
+
+	result, err := scraper.Scrape()
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	projects := result.([]Project)
+
+	if peak := maxInFlight.Load(); peak > 3 {
+		t.Errorf("Expected at most 3 concurrent requests, got %d", peak)
+	}
+
+	// Unmarked repositories are every third one
+	if len(projects) != count-count/3 {
+		t.Fatalf("Expected %d projects, got %d", count-count/3, len(projects))
+	}
+	for i := 1; i < len(projects); i++ {
+		if projects[i-1].Name >= projects[i].Name {
+			t.Errorf("Expected projects sorted by name, got %s before %s", projects[i-1].Name, projects[i].Name)
+		}
+	}
+
+	decisions := scraper.Decisions()
+	if len(decisions) != count {
+		t.Fatalf("Expected %d decisions, got %d", count, len(decisions))
+	}
+	for i, decision := range decisions {
+		if want := fmt.Sprintf("testuser/repo-%02d", i); decision.Repo != want {
+			t.Errorf("Decision %d: expected %s in listing order, got %s", i, want, decision.Repo)
+		}
+	}
+
+	// The marker check and the image extraction share one README fetch
+	for name, fetches := range readmeFetches {
+		if fetches != 1 {
+			t.Errorf("Expected README of %s to be fetched once, got %d", name, fetches)
+		}
+	}
+}
+
+func TestGitHubScraper_ScrapeContextCancelled(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int64
+	var mu sync.Mutex
+	server := newPoolTestServer(t, 4, &inFlight, &maxInFlight, make(map[string]int), &mu)
+	defer server.Close()
+
+	scraper := NewGitHubScraper("testuser", "token", newMockCache())
+	scraper.client = server.Client()
+	scraper.apiBase = server.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := scraper.ScrapeContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestReadmeMemo(t *testing.T) {
+	memo := newReadmeMemo()
+	var calls atomic.Int64
+	fetch := func() (string, string, error) {
+		calls.Add(1)
+		time.Sleep(5 * time.Millisecond)
+		return "# Readme", "README.md", nil
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			content, name, err := memo.load("owner/repo", fetch)
+			if err != nil || content != "# Readme" || name != "README.md" {
+				t.Errorf("Unexpected memo result: %q %q %v", content, name, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected a single fetch for concurrent lookups, got %d", calls.Load())
+	}
+
+	var nilMemo *readmeMemo
+	if _, _, err := nilMemo.load("owner/repo", fetch); err != nil || calls.Load() != 2 {
+		t.Errorf("Expected a nil memo to fetch directly (calls: %d, err: %v)", calls.Load(), err)
+	}
+}
+
+func TestSleepContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	if err := sleepContext(ctx, time.Minute); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Expected a cancelled sleep to return immediately")
+	}
+	if err := sleepContext(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("Expected a completed sleep, got %v", err)
+	}
+}
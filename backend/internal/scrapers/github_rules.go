@@ -0,0 +1,110 @@
+package scrapers
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// InclusionRules decide which repositories are considered for the portfolio.
+// They are evaluated before any marker file is fetched, so excluded
+// repositories cost no further API requests. Private repositories are
+// always excluded.
+type InclusionRules struct {
+	IncludeForks     bool
+	IncludeArchived  bool
+	IncludeTemplates bool
+
+	// AllowTopics, when set, requires at least one of these topics
+	AllowTopics []string
+	// DenyTopics excludes repositories carrying any of these topics
+	DenyTopics []string
+
+	// IncludeNames, when set, requires the repository to match one of these
+	// globs; ExcludeNames excludes matching repositories. Globs containing a
+	// slash match "owner/name", all others match the name alone.
+	IncludeNames []string
+	ExcludeNames []string
+}
+
+// Validate reports malformed name globs
+func (r InclusionRules) Validate() error {
+	for _, pattern := range slices.Concat(r.IncludeNames, r.ExcludeNames) {
+		if _, err := path.Match(strings.ToLower(pattern), ""); err != nil {
+			return fmt.Errorf("invalid repository name pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// RepoDecision records whether a repository was published and why
+type RepoDecision struct {
+	Repo     string `json:"repo"` // owner/name
+	Included bool   `json:"included"`
+	Reason   string `json:"reason"`
+}
+
+// evaluate applies the rules to a repository and explains the outcome.
+// An included repository still needs a portfolio marker to be published.
+func (r InclusionRules) evaluate(repo GitHubRepo, owner string) (bool, string) {
+	fullName := strings.ToLower(owner + "/" + repo.Name)
+
+	switch {
+	case repo.Private:
+		return false, "private repository"
+	case repo.Fork && !r.IncludeForks:
+		return false, "fork (forks are excluded)"
+	case repo.Archived && !r.IncludeArchived:
+		return false, "archived (archived repositories are excluded)"
+	case repo.IsTemplate && !r.IncludeTemplates:
+		return false, "template (template repositories are excluded)"
+	}
+
+	if pattern, ok := matchName(r.ExcludeNames, fullName); ok {
+		return false, fmt.Sprintf("name matches exclude pattern %q", pattern)
+	}
+	if len(r.IncludeNames) > 0 {
+		if _, ok := matchName(r.IncludeNames, fullName); !ok {
+			return false, "name matches no include pattern"
+		}
+	}
+
+	if topic, ok := matchTopic(r.DenyTopics, repo.Topics); ok {
+		return false, fmt.Sprintf("topic %q is denied", topic)
+	}
+	if len(r.AllowTopics) > 0 {
+		if _, ok := matchTopic(r.AllowTopics, repo.Topics); !ok {
+			return false, "no allowed topic"
+		}
+	}
+
+	return true, "passed inclusion rules"
+}
+
+// matchName returns the first glob matching a lowercased "owner/name"
+func matchName(patterns []string, fullName string) (string, bool) {
+	name := fullName[strings.Index(fullName, "/")+1:]
+	for _, pattern := range patterns {
+		target := name
+		if strings.Contains(pattern, "/") {
+			target = fullName
+		}
+		if ok, _ := path.Match(strings.ToLower(pattern), target); ok {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// matchTopic returns the first of topics found in repoTopics
+func matchTopic(topics, repoTopics []string) (string, bool) {
+	for _, topic := range topics {
+		for _, repoTopic := range repoTopics {
+			if strings.EqualFold(topic, repoTopic) {
+				return topic, true
+			}
+		}
+	}
+	return "", false
+}
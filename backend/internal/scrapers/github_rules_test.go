@@ -0,0 +1,196 @@
+package scrapers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestInclusionRules_Evaluate(t *testing.T) {
+	tests := []struct {
+		name     string
+		owner    string
+		rules    InclusionRules
+		repo     GitHubRepo
+		included bool
+		reason   string
+	}{
+		{
+			name:     "plain repository",
+			repo:     GitHubRepo{Name: "app"},
+			included: true,
+			reason:   "passed inclusion rules",
+		},
+		{
+			name:   "private repositories are always excluded",
+			rules:  InclusionRules{IncludeForks: true},
+			repo:   GitHubRepo{Name: "secret", Private: true},
+			reason: "private repository",
+		},
+		{
+			name:   "forks excluded by default",
+			repo:   GitHubRepo{Name: "upstream", Fork: true},
+			reason: "fork (forks are excluded)",
+		},
+		{
+			name:     "forks included on request",
+			rules:    InclusionRules{IncludeForks: true},
+			repo:     GitHubRepo{Name: "upstream", Fork: true},
+			included: true,
+			reason:   "passed inclusion rules",
+		},
+		{
+			name:   "archived excluded by default",
+			repo:   GitHubRepo{Name: "old", Archived: true},
+			reason: "archived (archived repositories are excluded)",
+		},
+		{
+			name:   "templates excluded by default",
+			repo:   GitHubRepo{Name: "starter", IsTemplate: true},
+			reason: "template (template repositories are excluded)",
+		},
+		{
+			name:   "exclude glob on name",
+			rules:  InclusionRules{ExcludeNames: []string{"*-old"}},
+			repo:   GitHubRepo{Name: "Site-Old"},
+			reason: `name matches exclude pattern "*-old"`,
+		},
+		{
+			name:   "exclude glob on owner/name",
+			owner:  "acme",
+			rules:  InclusionRules{ExcludeNames: []string{"acme/*"}},
+			repo:   GitHubRepo{Name: "tool"},
+			reason: `name matches exclude pattern "acme/*"`,
+		},
+		{
+			name:   "include globs restrict",
+			rules:  InclusionRules{IncludeNames: []string{"homepage", "go-*"}},
+			repo:   GitHubRepo{Name: "rust-thing"},
+			reason: "name matches no include pattern",
+		},
+		{
+			name:     "include glob matches",
+			rules:    InclusionRules{IncludeNames: []string{"homepage", "go-*"}},
+			repo:     GitHubRepo{Name: "go-tool"},
+			included: true,
+			reason:   "passed inclusion rules",
+		},
+		{
+			name:   "denied topic wins over allowed topic",
+			rules:  InclusionRules{AllowTopics: []string{"portfolio"}, DenyTopics: []string{"wip"}},
+			repo:   GitHubRepo{Name: "app", Topics: []string{"portfolio", "WIP"}},
+			reason: `topic "wip" is denied`,
+		},
+		{
+			name:   "allowed topic required",
+			rules:  InclusionRules{AllowTopics: []string{"portfolio"}},
+			repo:   GitHubRepo{Name: "app", Topics: []string{"go"}},
+			reason: "no allowed topic",
+		},
+		{
+			name:     "allowed topic present",
+			rules:    InclusionRules{AllowTopics: []string{"portfolio"}},
+			repo:     GitHubRepo{Name: "app", Topics: []string{"go", "portfolio"}},
+			included: true,
+			reason:   "passed inclusion rules",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner := tt.owner
+			if owner == "" {
+				owner = "testuser"
+			}
+			included, reason := tt.rules.evaluate(tt.repo, owner)
+			if included != tt.included || reason != tt.reason {
+				t.Errorf("Expected (%v, %q), got (%v, %q)", tt.included, tt.reason, included, reason)
+			}
+		})
+	}
+}
+
+func TestInclusionRules_Validate(t *testing.T) {
+	if err := (InclusionRules{IncludeNames: []string{"go-*"}, ExcludeNames: []string{"acme/*"}}).Validate(); err != nil {
+		t.Errorf("Expected valid globs, got %v", err)
+	}
+	if err := (InclusionRules{ExcludeNames: []string{"[broken"}}).Validate(); err == nil {
+		t.Error("Expected an error for a malformed glob")
+	}
+
+	scraper := NewGitHubScraper("testuser", "", newMockCache())
+	if err := scraper.SetInclusionRules(InclusionRules{IncludeNames: []string{"[broken"}}); err == nil {
+		t.Error("Expected SetInclusionRules to reject a malformed glob")
+	}
+}
+
+func TestGitHubScraper_Decisions(t *testing.T) {
+	var mu sync.Mutex
+	requested := make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path] = true
+		mu.Unlock()
+
+		var body string
+		switch {
+		case r.URL.Path == "/users/testuser/repos":
+			body = `[
+				{"name": "app", "owner": {"login": "testuser"}},
+				{"name": "forked", "fork": true, "owner": {"login": "testuser"}},
+				{"name": "hidden", "owner": {"login": "testuser"}},
+				{"name": "plain", "owner": {"login": "testuser"}}
+			]`
+		case r.URL.Path == "/repos/testuser/plain/contents":
+			body = `[]`
+		case strings.HasSuffix(r.URL.Path, "/contents"):
+			body = `[{"name": ".portfolio", "type": "file"}]`
+		case r.URL.Path == "/repos/testuser/hidden/contents/.portfolio":
+			body = `{"hidden": true}`
+		case strings.HasSuffix(r.URL.Path, "/contents/.portfolio"):
+			body = `{"featured": true}`
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatalf("Failed to write response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	scraper := NewGitHubScraper("testuser", "token", newMockCache())
+	scraper.client = server.Client()
+	scraper.apiBase = server.URL
+
+	result, err := scraper.Scrape()
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if projects := result.([]Project); len(projects) != 1 || projects[0].Name != "app" {
+		t.Fatalf("Expected only app to be published, got %+v", projects)
+	}
+
+	if requested["/repos/testuser/forked/contents"] {
+		t.Error("Expected no marker lookup for an excluded fork")
+	}
+
+	expected := []RepoDecision{
+		{Repo: "testuser/app", Included: true, Reason: "marked via .portfolio file"},
+		{Repo: "testuser/forked", Reason: "fork (forks are excluded)"},
+		{Repo: "testuser/hidden", Reason: "hidden via .portfolio file"},
+		{Repo: "testuser/plain", Reason: "no portfolio marker"},
+	}
+	decisions := scraper.Decisions()
+	if len(decisions) != len(expected) {
+		t.Fatalf("Expected %d decisions, got %+v", len(expected), decisions)
+	}
+	for i, want := range expected {
+		if decisions[i] != want {
+			t.Errorf("Decision %d: expected %+v, got %+v", i, want, decisions[i])
+		}
+	}
+}
@@ -0,0 +1,402 @@
+package scrapers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// PortfolioSchemaVersion is the newest .portfolio schema version understood
+// by the scraper. Files without schema_version are treated as version 1.
+// The published schema lives in schemas/portfolio.v1.schema.json.
+const PortfolioSchemaVersion = 1
+
+// portfolioFiles are the supported marker file names, in lookup order
+var portfolioFiles = []string{portfolioFile, ".portfolio.json", ".portfolio.yml", ".portfolio.yaml", ".portfolio.toml"}
+
+// PortfolioIssue is a single problem found while validating a .portfolio file
+type PortfolioIssue struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// PortfolioValidationError lists every problem found in a .portfolio file
+type PortfolioValidationError struct {
+	Repo   string           `json:"repo"`
+	File   string           `json:"file"`
+	Issues []PortfolioIssue `json:"issues"`
+}
+
+// Error implements the error interface
+func (e *PortfolioValidationError) Error() string {
+	parts := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		parts = append(parts, fmt.Sprintf("%s: %s", issue.Field, issue.Message))
+	}
+	return fmt.Sprintf("invalid %s: %s", e.File, strings.Join(parts, "; "))
+}
+
+// fieldValidator checks the value of a single .portfolio field
+type fieldValidator func(field string, value any) []PortfolioIssue
+
+// portfolioFieldValidators defines every field allowed at the top level of a
+// .portfolio file; anything else is reported as an unknown field
+var portfolioFieldValidators = map[string]fieldValidator{
+	"$schema":        validateString,
+	"schema_version": validateSchemaVersion,
+	"description":    validateString,
+	"featured":       validateBool,
+	"priority":       validateInteger,
+	"images":         validateStringList,
+	"tags":           validateStringList,
+	"links":          validateLinks,
+	"hidden":         validateBool,
+	"category":       validateNonEmptyString,
+	"order_group":    validateInteger,
+	"display_name":   validateNonEmptyString,
+	"start_date":     validateDate,
+	"end_date":       validateDate,
+}
+
+// portfolioDateLayouts are the accepted start_date/end_date precisions
+var portfolioDateLayouts = []string{"2006-01-02", "2006-01", "2006"}
+
+// linkFieldValidators defines the fields allowed in a links entry
+var linkFieldValidators = map[string]fieldValidator{
+	"name": validateString,
+	"url":  validateURL,
+	"icon": validateString,
+}
+
+// parsePortfolioFile decodes a .portfolio file in the format implied by its
+// name (JSON, YAML or TOML), validates it against the schema and returns
+// the resulting metadata. Validation problems are returned as a
+// *PortfolioValidationError.
+func parsePortfolioFile(name, content string) (PortfolioMetadata, error) {
+	fields, err := decodePortfolioFields(name, content)
+	if err != nil {
+		return PortfolioMetadata{}, fmt.Errorf("invalid %s file: %w", name, err)
+	}
+
+	return metadataFromFields(name, fields)
+}
+
+// metadataFromFields validates decoded .portfolio fields and converts them
+// to PortfolioMetadata; file names the source in validation errors
+func metadataFromFields(file string, fields map[string]any) (PortfolioMetadata, error) {
+	if issues := validatePortfolioFields(fields); len(issues) > 0 {
+		return PortfolioMetadata{}, &PortfolioValidationError{File: file, Issues: issues}
+	}
+
+	// The fields are known to be well-formed, so a JSON round trip maps
+	// them onto the struct regardless of the source format
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return PortfolioMetadata{}, fmt.Errorf("failed to convert %s: %w", file, err)
+	}
+	var metadata PortfolioMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return PortfolioMetadata{}, fmt.Errorf("failed to convert %s: %w", file, err)
+	}
+	if metadata.SchemaVersion == 0 {
+		metadata.SchemaVersion = 1
+	}
+	return metadata, nil
+}
+
+// decodePortfolioFields parses a .portfolio file into a generic field map
+func decodePortfolioFields(name, content string) (map[string]any, error) {
+	fields := make(map[string]any)
+
+	switch path.Ext(name) {
+	case ".yml", ".yaml":
+		if err := yaml.Unmarshal([]byte(content), &fields); err != nil {
+			return nil, err
+		}
+	case ".toml":
+		if err := toml.Unmarshal([]byte(content), &fields); err != nil {
+			return nil, err
+		}
+	default:
+		decoder := json.NewDecoder(bytes.NewReader([]byte(content)))
+		decoder.UseNumber()
+		if err := decoder.Decode(&fields); err != nil {
+			return nil, err
+		}
+	}
+
+	for key, value := range fields {
+		fields[key] = normalizeDecoded(value)
+	}
+	return fields, nil
+}
+
+// normalizeDecoded converts decoder-specific container types (such as the
+// []map[string]any TOML produces for arrays of tables) to []any and map[string]any
+func normalizeDecoded(value any) any {
+	switch v := value.(type) {
+	case []map[string]any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = normalizeDecoded(item)
+		}
+		return items
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeDecoded(item)
+		}
+		return v
+	case map[string]any:
+		for key, item := range v {
+			v[key] = normalizeDecoded(item)
+		}
+		return v
+	case time.Time:
+		// YAML and TOML decode bare dates such as 2024-03-01 natively
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(time.RFC3339)
+	default:
+		return value
+	}
+}
+
+// validatePortfolioFields checks every field against the schema and returns
+// the issues sorted by field name
+func validatePortfolioFields(fields map[string]any) []PortfolioIssue {
+	issues := validateObject("", fields, portfolioFieldValidators)
+
+	// Dates may differ in precision (2024 vs 2024-06), so they are compared
+	// on their common prefix, which orders correctly for these layouts
+	start, startOK := fields["start_date"].(string)
+	end, endOK := fields["end_date"].(string)
+	if startOK && endOK {
+		_, startErr := parsePortfolioDate(start)
+		_, endErr := parsePortfolioDate(end)
+		n := min(len(start), len(end))
+		if startErr == nil && endErr == nil && end[:n] < start[:n] {
+			issues = append(issues, PortfolioIssue{Field: "end_date", Message: "must not be before start_date"})
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Field < issues[j].Field
+	})
+	return issues
+}
+
+// validateObject validates the fields of an object against a set of validators
+func validateObject(prefix string, fields map[string]any, validators map[string]fieldValidator) []PortfolioIssue {
+	issues := make([]PortfolioIssue, 0)
+	for key, value := range fields {
+		field := prefix + key
+		validate, known := validators[key]
+		if !known {
+			message := "unknown field"
+			if suggestion := closestField(key, validators); suggestion != "" {
+				message = fmt.Sprintf("unknown field (did you mean %q?)", suggestion)
+			}
+			issues = append(issues, PortfolioIssue{Field: field, Message: message})
+			continue
+		}
+		issues = append(issues, validate(field, value)...)
+	}
+	return issues
+}
+
+// closestField suggests a known field name for a likely typo
+func closestField(key string, validators map[string]fieldValidator) string {
+	best, bestDistance := "", 3
+	for candidate := range validators {
+		if d := levenshtein(strings.ToLower(key), candidate); d < bestDistance || (d == bestDistance && candidate < best) {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr := make([]int, len(rb)+1)
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(rb)]
+}
+
+func validateString(field string, value any) []PortfolioIssue {
+	if _, ok := value.(string); !ok {
+		return []PortfolioIssue{{Field: field, Message: fmt.Sprintf("must be a string, got %s", typeName(value))}}
+	}
+	return nil
+}
+
+func validateNonEmptyString(field string, value any) []PortfolioIssue {
+	if issues := validateString(field, value); len(issues) > 0 {
+		return issues
+	}
+	if strings.TrimSpace(value.(string)) == "" {
+		return []PortfolioIssue{{Field: field, Message: "must not be empty"}}
+	}
+	return nil
+}
+
+func validateDate(field string, value any) []PortfolioIssue {
+	if issues := validateString(field, value); len(issues) > 0 {
+		return issues
+	}
+	if _, err := parsePortfolioDate(value.(string)); err != nil {
+		return []PortfolioIssue{{Field: field, Message: "must be a date in the form YYYY, YYYY-MM or YYYY-MM-DD"}}
+	}
+	return nil
+}
+
+// parsePortfolioDate parses a date in any of portfolioDateLayouts
+func parsePortfolioDate(value string) (time.Time, error) {
+	for _, layout := range portfolioDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+func validateBool(field string, value any) []PortfolioIssue {
+	if _, ok := value.(bool); !ok {
+		return []PortfolioIssue{{Field: field, Message: fmt.Sprintf("must be true or false, got %s", typeName(value))}}
+	}
+	return nil
+}
+
+func validateInteger(field string, value any) []PortfolioIssue {
+	if _, ok := asInteger(value); !ok {
+		return []PortfolioIssue{{Field: field, Message: fmt.Sprintf("must be an integer, got %s", typeName(value))}}
+	}
+	return nil
+}
+
+func validateSchemaVersion(field string, value any) []PortfolioIssue {
+	version, ok := asInteger(value)
+	if !ok {
+		return []PortfolioIssue{{Field: field, Message: fmt.Sprintf("must be an integer, got %s", typeName(value))}}
+	}
+	if version < 1 || version > PortfolioSchemaVersion {
+		return []PortfolioIssue{{Field: field, Message: fmt.Sprintf("unsupported version %d (supported: 1-%d)", version, PortfolioSchemaVersion)}}
+	}
+	return nil
+}
+
+func validateStringList(field string, value any) []PortfolioIssue {
+	items, ok := value.([]any)
+	if !ok {
+		return []PortfolioIssue{{Field: field, Message: fmt.Sprintf("must be a list of strings, got %s", typeName(value))}}
+	}
+	issues := make([]PortfolioIssue, 0)
+	for i, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			issues = append(issues, PortfolioIssue{Field: fmt.Sprintf("%s[%d]", field, i), Message: fmt.Sprintf("must be a string, got %s", typeName(item))})
+		} else if strings.TrimSpace(s) == "" {
+			issues = append(issues, PortfolioIssue{Field: fmt.Sprintf("%s[%d]", field, i), Message: "must not be empty"})
+		}
+	}
+	return issues
+}
+
+func validateURL(field string, value any) []PortfolioIssue {
+	s, ok := value.(string)
+	if !ok {
+		return []PortfolioIssue{{Field: field, Message: fmt.Sprintf("must be a string, got %s", typeName(value))}}
+	}
+	if !strings.HasPrefix(s, "http://") && !strings.HasPrefix(s, "https://") {
+		return []PortfolioIssue{{Field: field, Message: "must be an http(s) URL"}}
+	}
+	return nil
+}
+
+func validateLinks(field string, value any) []PortfolioIssue {
+	items, ok := value.([]any)
+	if !ok {
+		return []PortfolioIssue{{Field: field, Message: fmt.Sprintf("must be a list of links, got %s", typeName(value))}}
+	}
+	issues := make([]PortfolioIssue, 0)
+	for i, item := range items {
+		prefix := fmt.Sprintf("%s[%d]", field, i)
+		link, ok := item.(map[string]any)
+		if !ok {
+			issues = append(issues, PortfolioIssue{Field: prefix, Message: fmt.Sprintf("must be an object, got %s", typeName(item))})
+			continue
+		}
+		issues = append(issues, validateObject(prefix+".", link, linkFieldValidators)...)
+		for _, required := range []string{"name", "url"} {
+			if _, present := link[required]; !present {
+				issues = append(issues, PortfolioIssue{Field: prefix + "." + required, Message: "is required"})
+			}
+		}
+	}
+	return issues
+}
+
+// asInteger converts the numeric types produced by the JSON, YAML and TOML
+// decoders to an int, rejecting fractional values
+func asInteger(value any) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case uint64:
+		return int(v), true
+	case float64:
+		if v == math.Trunc(v) {
+			return int(v), true
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return int(n), true
+		}
+	}
+	return 0, false
+}
+
+// typeName describes a decoded value for error messages
+func typeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int64, uint64, float64, json.Number:
+		return "number"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,221 @@
+package scrapers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// README markers opt a repository in without a .portfolio file and carry the
+// same fields. Two structured forms are recognised:
+//
+//	<!-- PORTFOLIO featured=true priority=5 category="Web Apps" -->
+//
+//	<!-- PORTFOLIO
+//	featured: true
+//	images: [docs/screenshot.png]
+//	-->
+//
+// and a YAML front-matter block at the very top of the README with a
+// portfolio key (either `portfolio: true` or a mapping of fields). Markers in
+// fenced code blocks and inline code are ignored, so READMEs documenting the
+// marker are not picked up. The bare 🎨 emoji is only honoured in legacy mode.
+const (
+	readmeFrontMatterMarker = "front matter"
+	readmeCommentMarker     = "<!-- PORTFOLIO --> comment"
+	legacyEmojiMarker       = "🎨"
+)
+
+var (
+	portfolioCommentPattern = regexp.MustCompile(`(?s)<!--\s*PORTFOLIO\b(.*?)-->`)
+	markerAttributePattern  = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*=`)
+	inlineCodePattern       = regexp.MustCompile("`[^`\n]+`")
+)
+
+// parseReadmeMarker looks for a structured portfolio marker in a README and
+// returns its kind ("" if there is none) and the undecoded fields it carries
+func parseReadmeMarker(readme string) (string, map[string]any, error) {
+	readme = strings.ReplaceAll(strings.TrimPrefix(readme, "\ufeff"), "\r\n", "\n")
+
+	if block, ok := frontMatter(readme); ok {
+		fields, found, err := parseFrontMatter(block)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid README front matter: %w", err)
+		}
+		if found {
+			return readmeFrontMatterMarker, fields, nil
+		}
+	}
+
+	match := portfolioCommentPattern.FindStringSubmatch(stripCode(readme))
+	if match == nil {
+		return "", nil, nil
+	}
+	fields, err := parseMarkerComment(match[1])
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid <!-- PORTFOLIO --> comment: %w", err)
+	}
+	return readmeCommentMarker, fields, nil
+}
+
+// frontMatter returns the YAML block delimited by --- lines at the very
+// start of a README
+func frontMatter(readme string) (string, bool) {
+	lines := strings.Split(readme, "\n")
+	if len(lines) < 2 || strings.TrimRight(lines[0], " \t") != "---" {
+		return "", false
+	}
+	for i := 1; i < len(lines); i++ {
+		if line := strings.TrimRight(lines[i], " \t"); line == "---" || line == "..." {
+			return strings.Join(lines[1:i], "\n"), true
+		}
+	}
+	return "", false
+}
+
+// parseFrontMatter extracts the portfolio key of a front-matter block. Other
+// keys belong to other tools and are ignored.
+func parseFrontMatter(block string) (map[string]any, bool, error) {
+	document := make(map[string]any)
+	if err := yaml.Unmarshal([]byte(block), &document); err != nil {
+		return nil, false, err
+	}
+
+	switch portfolio := document["portfolio"].(type) {
+	case nil:
+		return nil, false, nil
+	case bool:
+		return map[string]any{}, portfolio, nil
+	case map[string]any:
+		fields := make(map[string]any, len(portfolio))
+		for key, value := range portfolio {
+			fields[key] = normalizeDecoded(value)
+		}
+		return fields, true, nil
+	default:
+		return nil, false, fmt.Errorf("portfolio must be true or a mapping of fields, got %s", typeName(portfolio))
+	}
+}
+
+// parseMarkerComment decodes the body of a <!-- PORTFOLIO ... --> comment,
+// which is empty, a list of key=value attributes or a YAML mapping
+func parseMarkerComment(body string) (map[string]any, error) {
+	// Drop the rest of the PORTFOLIO line when the fields start below it
+	if i := strings.IndexByte(body, '\n'); i >= 0 && strings.TrimSpace(body[:i]) == "" {
+		body = body[i+1:]
+	}
+
+	trimmed := strings.TrimSpace(body)
+	switch {
+	case trimmed == "":
+		return map[string]any{}, nil
+	case markerAttributePattern.MatchString(trimmed):
+		return parseMarkerAttributes(trimmed)
+	}
+
+	fields := make(map[string]any)
+	if err := yaml.Unmarshal([]byte(body), &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range fields {
+		fields[key] = normalizeDecoded(value)
+	}
+	return fields, nil
+}
+
+// parseMarkerAttributes parses whitespace-separated key=value pairs. Values
+// use YAML flow syntax: true, 5, "quoted text" or [a.png, b.png].
+func parseMarkerAttributes(text string) (map[string]any, error) {
+	fields := make(map[string]any)
+	for text = strings.TrimSpace(text); text != ""; text = strings.TrimSpace(text) {
+		attribute := markerAttributePattern.FindString(text)
+		if attribute == "" {
+			return nil, fmt.Errorf("expected key=value at %q", firstWord(text))
+		}
+		key := strings.TrimSuffix(attribute, "=")
+		if _, ok := fields[key]; ok {
+			return nil, fmt.Errorf("duplicate field %s", key)
+		}
+
+		text = text[len(attribute):]
+		end := attributeValueEnd(text)
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated value for %s", key)
+		}
+
+		var value any
+		if err := yaml.Unmarshal([]byte(text[:end]), &value); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		fields[key] = normalizeDecoded(value)
+		text = text[end:]
+	}
+	return fields, nil
+}
+
+// attributeValueEnd returns the length of the attribute value at the start
+// of s: up to the first whitespace outside quotes and brackets, or -1 if a
+// quote or bracket is left open
+func attributeValueEnd(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case depth == 0 && strings.ContainsRune(" \t\r\n", rune(c)):
+			return i
+		}
+	}
+	if quote != 0 || depth != 0 {
+		return -1
+	}
+	return len(s)
+}
+
+// stripCode blanks fenced code blocks and inline code spans, which show
+// markdown source rather than being part of the document
+func stripCode(readme string) string {
+	lines := strings.Split(readme, "\n")
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) && strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1])) == "" {
+				fence = ""
+			}
+			lines[i] = ""
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+			for len(fence) < len(trimmed) && trimmed[len(fence)] == fence[0] {
+				fence += fence[:1]
+			}
+			lines[i] = ""
+		default:
+			lines[i] = inlineCodePattern.ReplaceAllString(line, "")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// firstWord returns s up to its first whitespace, for error messages
+func firstWord(s string) string {
+	if fields := strings.Fields(s); len(fields) > 0 {
+		return fields[0]
+	}
+	return s
+}
@@ -0,0 +1,188 @@
+package scrapers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParseReadmeMarker(t *testing.T) {
+	tests := []struct {
+		name   string
+		readme string
+		kind   string
+		fields map[string]any
+	}{
+		{
+			name:   "bare comment",
+			readme: "# App\n<!-- PORTFOLIO -->\n",
+			kind:   readmeCommentMarker,
+			fields: map[string]any{},
+		},
+		{
+			name:   "comment attributes",
+			readme: `<!-- PORTFOLIO featured=true priority=5 category="Web Apps" tags=[go, cli] -->`,
+			kind:   readmeCommentMarker,
+			fields: map[string]any{
+				"featured": true,
+				"priority": 5,
+				"category": "Web Apps",
+				"tags":     []any{"go", "cli"},
+			},
+		},
+		{
+			name:   "comment YAML block",
+			readme: "# App\n<!-- PORTFOLIO\n  description: A tool\n  images:\n    - docs/shot.png\n-->\n",
+			kind:   readmeCommentMarker,
+			fields: map[string]any{
+				"description": "A tool",
+				"images":      []any{"docs/shot.png"},
+			},
+		},
+		{
+			name:   "front matter",
+			readme: "---\ntitle: Other tool\nportfolio:\n  featured: true\n  start_date: 2023-04\n---\n# App\n",
+			kind:   readmeFrontMatterMarker,
+			fields: map[string]any{"featured": true, "start_date": "2023-04"},
+		},
+		{
+			name:   "front matter flag with CRLF line endings",
+			readme: "---\r\nportfolio: true\r\n---\r\n# App\r\n",
+			kind:   readmeFrontMatterMarker,
+			fields: map[string]any{},
+		},
+		{
+			name:   "front matter without portfolio key falls back to comment",
+			readme: "---\ntitle: Docs\n---\n<!-- PORTFOLIO priority=2 -->\n",
+			kind:   readmeCommentMarker,
+			fields: map[string]any{"priority": 2},
+		},
+		{
+			name:   "comment in fenced code block",
+			readme: "# Docs\n```markdown\n<!-- PORTFOLIO -->\n```\n",
+		},
+		{
+			name:   "comment in inline code",
+			readme: "Add `<!-- PORTFOLIO -->` to your README\n",
+		},
+		{
+			name:   "emoji is not a structured marker",
+			readme: "# Paint 🎨\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, fields, err := parseReadmeMarker(tt.readme)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if kind != tt.kind {
+				t.Errorf("Expected kind %q, got %q", tt.kind, kind)
+			}
+			if tt.kind != "" && !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Expected fields %#v, got %#v", tt.fields, fields)
+			}
+		})
+	}
+}
+
+func TestParseReadmeMarker_Errors(t *testing.T) {
+	tests := map[string]string{
+		"unterminated quote": `<!-- PORTFOLIO description="open -->`,
+		"duplicate field":    `<!-- PORTFOLIO priority=1 priority=2 -->`,
+		"missing key":        `<!-- PORTFOLIO featured=true =5 -->`,
+		"front matter type":  "---\nportfolio: yes please\n---\n",
+		"YAML block syntax":  "<!-- PORTFOLIO\nfeatured: [true\n-->",
+	}
+	for name, readme := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := parseReadmeMarker(readme); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+// newReadmeServer serves the repositories of testuser, each with a README
+// taken from readmes (keyed by repository name)
+func newReadmeServer(t *testing.T, readmes map[string]string) *httptest.Server {
+	names := make([]string, 0, len(readmes))
+	for name := range readmes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch {
+		case r.URL.Path == "/users/testuser/repos":
+			repos := make([]string, 0, len(names))
+			for _, name := range names {
+				repos = append(repos, fmt.Sprintf(`{"name": %q, "owner": {"login": "testuser"}}`, name))
+			}
+			body = "[" + strings.Join(repos, ",") + "]"
+		case strings.HasSuffix(r.URL.Path, "/contents"):
+			body = `[{"name": "README.md", "type": "file"}]`
+		case strings.HasSuffix(r.URL.Path, "/contents/README.md"):
+			body = readmes[strings.Split(r.URL.Path, "/")[3]]
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Errorf("Failed to write response: %v", err)
+		}
+	}))
+}
+
+func TestGitHubScraper_ReadmeMarkers(t *testing.T) {
+	readmes := map[string]string{
+		"structured": "# Tool\n<!-- PORTFOLIO featured=true priority=5 -->\n",
+		"emoji":      "# Paint 🎨\n",
+		"invalid":    "# Broken\n<!-- PORTFOLIO featured=maybe -->\n",
+	}
+	server := newReadmeServer(t, readmes)
+	defer server.Close()
+
+	scrape := func(legacy bool) ([]Project, *GitHubScraper) {
+		scraper := NewGitHubScraper("testuser", "token", newMockCache())
+		scraper.client = server.Client()
+		scraper.apiBase = server.URL
+		scraper.SetLegacyEmojiMarker(legacy)
+		result, err := scraper.Scrape()
+		if err != nil {
+			t.Fatalf("Scrape failed: %v", err)
+		}
+		return result.([]Project), scraper
+	}
+
+	projects, scraper := scrape(false)
+	if len(projects) != 1 || projects[0].Name != "structured" {
+		t.Fatalf("Expected only the structured marker to count, got %+v", projects)
+	}
+	if !projects[0].Featured || projects[0].Priority != 5 {
+		t.Errorf("Expected marker fields to be applied, got featured=%v priority=%d", projects[0].Featured, projects[0].Priority)
+	}
+	// Repositories are listed by name, so structured is checked last
+	if decisions := scraper.Decisions(); decisions[2].Reason != "marked via README.md <!-- PORTFOLIO --> comment" {
+		t.Errorf("Unexpected decision reason: %q", decisions[2].Reason)
+	}
+
+	portfolioErrors := scraper.PortfolioErrors()
+	if len(portfolioErrors) != 1 || portfolioErrors[0].Repo != "testuser/invalid" {
+		t.Fatalf("Expected a validation error for testuser/invalid, got %v", portfolioErrors)
+	}
+	if portfolioErrors[0].File != "README.md <!-- PORTFOLIO --> comment" {
+		t.Errorf("Unexpected error source %q", portfolioErrors[0].File)
+	}
+
+	projects, _ = scrape(true)
+	if len(projects) != 2 {
+		t.Errorf("Expected the emoji marker to count in legacy mode, got %+v", projects)
+	}
+}
@@ -0,0 +1,237 @@
+package scrapers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParsePortfolioFile_Formats(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "JSON",
+			file: ".portfolio",
+			content: `{
+				"schema_version": 1,
+				"description": "Homelab automation",
+				"featured": true,
+				"priority": 5,
+				"tags": ["ansible", "docker"],
+				"links": [{"name": "Live", "url": "https://example.com"}]
+			}`,
+		},
+		{
+			name: "YAML",
+			file: ".portfolio.yml",
+			content: `schema_version: 1
+description: Homelab automation
+featured: true
+priority: 5
+tags: [ansible, docker]
+links:
+  - name: Live
+    url: https://example.com
+`,
+		},
+		{
+			name: "TOML",
+			file: ".portfolio.toml",
+			content: `schema_version = 1
+description = "Homelab automation"
+featured = true
+priority = 5
+tags = ["ansible", "docker"]
+
+[[links]]
+name = "Live"
+url = "https://example.com"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata, err := parsePortfolioFile(tt.file, tt.content)
+			if err != nil {
+				t.Fatalf("Expected valid metadata, got error: %v", err)
+			}
+			if metadata.Description != "Homelab automation" || !metadata.Featured {
+				t.Errorf("Unexpected metadata: %+v", metadata)
+			}
+			if metadata.Priority == nil || *metadata.Priority != 5 {
+				t.Errorf("Expected priority 5, got %v", metadata.Priority)
+			}
+			if len(metadata.Tags) != 2 || metadata.Tags[1] != "docker" {
+				t.Errorf("Expected tags [ansible docker], got %v", metadata.Tags)
+			}
+			if len(metadata.Links) != 1 || metadata.Links[0].URL != "https://example.com" {
+				t.Errorf("Expected one link, got %+v", metadata.Links)
+			}
+		})
+	}
+}
+
+func TestParsePortfolioFile_DefaultsSchemaVersion(t *testing.T) {
+	metadata, err := parsePortfolioFile(".portfolio", `{"featured": true}`)
+	if err != nil {
+		t.Fatalf("Expected valid metadata, got error: %v", err)
+	}
+	if metadata.SchemaVersion != 1 {
+		t.Errorf("Expected missing schema_version to default to 1, got %d", metadata.SchemaVersion)
+	}
+}
+
+func TestParsePortfolioFile_DisplayFields(t *testing.T) {
+	content := `hidden: true
+category: Infrastructure
+order_group: 2
+display_name: Homelab
+start_date: 2023-04-01
+end_date: "2024"
+`
+	metadata, err := parsePortfolioFile(".portfolio.yaml", content)
+	if err != nil {
+		t.Fatalf("Expected valid metadata, got error: %v", err)
+	}
+	if !metadata.Hidden || metadata.Category != "Infrastructure" || metadata.OrderGroup != 2 || metadata.DisplayName != "Homelab" {
+		t.Errorf("Unexpected metadata: %+v", metadata)
+	}
+	if metadata.StartDate != "2023-04-01" || metadata.EndDate != "2024" {
+		t.Errorf("Expected dates 2023-04-01 and 2024, got %q and %q", metadata.StartDate, metadata.EndDate)
+	}
+}
+
+func TestParsePortfolioFile_InvalidDates(t *testing.T) {
+	tests := []struct {
+		content string
+		field   string
+		message string
+	}{
+		{`{"start_date": "April 2023"}`, "start_date", "must be a date in the form YYYY, YYYY-MM or YYYY-MM-DD"},
+		{`{"start_date": "2023-05", "end_date": "2023-04-30"}`, "end_date", "must not be before start_date"},
+		{`{"category": " "}`, "category", "must not be empty"},
+	}
+
+	for _, tt := range tests {
+		_, err := parsePortfolioFile(".portfolio", tt.content)
+		var validationErr *PortfolioValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected a PortfolioValidationError for %s, got %v", tt.content, err)
+		}
+		if len(validationErr.Issues) != 1 || validationErr.Issues[0].Field != tt.field || validationErr.Issues[0].Message != tt.message {
+			t.Errorf("For %s expected %s: %s, got %+v", tt.content, tt.field, tt.message, validationErr.Issues)
+		}
+	}
+
+	if _, err := parsePortfolioFile(".portfolio", `{"start_date": "2023-05-10", "end_date": "2023-05"}`); err != nil {
+		t.Errorf("Expected end_date with coarser precision in the same month to be valid, got %v", err)
+	}
+}
+
+func TestParsePortfolioFile_ValidationErrors(t *testing.T) {
+	content := `{
+		"schema_version": 2,
+		"featurd": true,
+		"priority": "high",
+		"tags": ["go", 3],
+		"links": [{"name": "Docs"}, {"name": "Live", "url": "example.com", "colour": "red"}]
+	}`
+
+	_, err := parsePortfolioFile(".portfolio", content)
+	var validationErr *PortfolioValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a PortfolioValidationError, got %v", err)
+	}
+
+	expected := map[string]string{
+		"featurd":         `unknown field (did you mean "featured"?)`,
+		"links[0].url":    "is required",
+		"links[1].colour": "unknown field",
+		"links[1].url":    "must be an http(s) URL",
+		"priority":        "must be an integer, got string",
+		"schema_version":  "unsupported version 2 (supported: 1-1)",
+		"tags[1]":         "must be a string, got number",
+	}
+	if len(validationErr.Issues) != len(expected) {
+		t.Fatalf("Expected %d issues, got %d: %+v", len(expected), len(validationErr.Issues), validationErr.Issues)
+	}
+	for i, issue := range validationErr.Issues {
+		if want, ok := expected[issue.Field]; !ok || want != issue.Message {
+			t.Errorf("Unexpected issue %s: %q (want %q)", issue.Field, issue.Message, want)
+		}
+		if i > 0 && validationErr.Issues[i-1].Field > issue.Field {
+			t.Errorf("Expected issues sorted by field, got %s before %s", validationErr.Issues[i-1].Field, issue.Field)
+		}
+	}
+}
+
+func TestParsePortfolioFile_SyntaxError(t *testing.T) {
+	_, err := parsePortfolioFile(".portfolio.yaml", "featured: [true")
+	if err == nil {
+		t.Fatal("Expected a syntax error")
+	}
+	var validationErr *PortfolioValidationError
+	if errors.As(err, &validationErr) {
+		t.Error("Expected syntax errors not to be reported as validation errors")
+	}
+}
+
+func TestGitHubScraper_PortfolioErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.Path {
+		case "/users/testuser/repos":
+			body = `[{"name": "valid", "owner": {"login": "testuser"}}, {"name": "broken", "owner": {"login": "testuser"}}, {"name": "hidden", "owner": {"login": "testuser"}}]`
+		case "/repos/testuser/valid/contents":
+			body = `[{"name": ".portfolio.toml", "type": "file"}]`
+		case "/repos/testuser/valid/contents/.portfolio.toml":
+			body = "featured = true\n"
+		case "/repos/testuser/hidden/contents":
+			body = `[{"name": ".portfolio", "type": "file"}]`
+		case "/repos/testuser/hidden/contents/.portfolio":
+			body = `{"hidden": true}`
+		case "/repos/testuser/broken/contents":
+			body = `[{"name": ".portfolio.yml", "type": "file"}, {"name": "README.md", "type": "file"}]`
+		case "/repos/testuser/broken/contents/.portfolio.yml":
+			body = "featured: yes please\n"
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatalf("Failed to write response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	scraper := NewGitHubScraper("testuser", "token", newMockCache())
+	scraper.client = server.Client()
+	scraper.apiBase = server.URL
+
+	result, err := scraper.Scrape()
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	projects := result.([]Project)
+	if len(projects) != 1 || projects[0].Name != "valid" || !projects[0].Featured {
+		t.Fatalf("Expected only the valid project (broken is invalid, hidden is hidden), got %+v", projects)
+	}
+
+	portfolioErrors := scraper.PortfolioErrors()
+	if len(portfolioErrors) != 1 {
+		t.Fatalf("Expected 1 portfolio error, got %d", len(portfolioErrors))
+	}
+	got := portfolioErrors[0]
+	if got.Repo != "testuser/broken" || got.File != ".portfolio.yml" {
+		t.Errorf("Expected error for testuser/broken .portfolio.yml, got %s %s", got.Repo, got.File)
+	}
+	if !strings.Contains(got.Error(), "featured: must be true or false, got string") {
+		t.Errorf("Unexpected error message: %s", got.Error())
+	}
+}
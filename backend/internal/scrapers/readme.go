@@ -0,0 +1,461 @@
+package scrapers
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// README digests give project cards more than the one-line GitHub
+// description: a summary (the first prose paragraph), highlights (the items
+// of a "Features" list) and optionally a small HTML excerpt of both. Only the
+// subset of markdown needed for that is parsed; everything else is skipped.
+const (
+	maxSummaryLength   = 320
+	minSummaryWords    = 4
+	maxHighlights      = 8
+	maxHighlightLength = 160
+)
+
+// markdownBlockKind is the type of a top-level markdown block
+type markdownBlockKind int
+
+const (
+	blockParagraph markdownBlockKind = iota
+	blockList
+	blockCode
+	blockHTML
+	blockQuote
+	blockTable
+)
+
+// markdownBlock is a top-level block of a README section
+type markdownBlock struct {
+	kind markdownBlockKind
+	// text is the raw markdown of paragraphs and quotes
+	text string
+	// items are the top-level list items; nested lists are dropped
+	items   []string
+	ordered bool
+}
+
+// readmeSection is a heading and the blocks up to the next heading. The
+// first section has level 0 and holds the content before any heading.
+type readmeSection struct {
+	level  int
+	title  string // plain text
+	blocks []markdownBlock
+}
+
+// readmeDigest is the content extracted from a README, as raw markdown
+type readmeDigest struct {
+	summary    string
+	highlights []string
+}
+
+var (
+	headingRegex        = regexp.MustCompile(`^(#{1,6})(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+	setextRegex         = regexp.MustCompile(`^(=+|-+)\s*$`)
+	thematicBreakRegex  = regexp.MustCompile(`^([-*_])(\s*[-*_]){2,}$`)
+	listItemRegex       = regexp.MustCompile(`^([-*+]|\d{1,9}[.)])(\s+|$)(.*)$`)
+	taskListRegex       = regexp.MustCompile(`^\[[ xX]\]\s+`)
+	highlightTitleRegex = regexp.MustCompile(`^((key|main|core)\s+)?(features?|highlights)$`)
+
+	// inlineTokenRegex matches the inline markdown understood by
+	// inlineRenderer. Capture groups: 1 code, 2-3 link text and target,
+	// 4 autolink, 5-6 strong, 7 emphasis, 8 strikethrough.
+	inlineTokenRegex = regexp.MustCompile(
+		`\[!\[[^\]]*\]\([^)]*\)\]\([^)]*\)` + // linked image (badges)
+			"|`([^`]+)`" +
+			`|!\[[^\]]*\]\([^)]*\)` + // image
+			`|\[([^\]]+)\]\(\s*<?((?:[^()\s<>]|\([^()\s]*\))*)>?(?:\s+"[^"]*")?\s*\)` +
+			`|<(https?://[^>\s]+)>` +
+			`|\*\*([^*]+)\*\*|__([^_]+)__` +
+			`|\*([^*\s][^*]*)\*` +
+			`|~~([^~]+)~~` +
+			`|<[^>]*>`) // raw HTML
+)
+
+// parseReadme splits README markdown into sections of top-level blocks
+func parseReadme(markdown string) []readmeSection {
+	markdown = strings.ReplaceAll(strings.TrimPrefix(markdown, "\ufeff"), "\r\n", "\n")
+	lines := strings.Split(markdown, "\n")
+
+	start := 0
+	if block, ok := frontMatter(markdown); ok {
+		start = 2
+		if block != "" {
+			start += strings.Count(block, "\n") + 1
+		}
+	}
+
+	p := &readmeParser{sections: []readmeSection{{}}}
+	for i := start; i < len(lines); i++ {
+		i = p.parseLine(lines, i)
+	}
+	p.flush()
+	return p.sections
+}
+
+// readmeParser holds the state of parseReadme
+type readmeParser struct {
+	sections  []readmeSection
+	paragraph []string
+	list      *markdownBlock
+	// listIndent is the content indentation of the current list item
+	listIndent int
+	// nested is true while inside a nested list, whose lines are dropped
+	nested bool
+	blank  bool
+}
+
+// parseLine consumes the block starting at lines[i] and returns the index
+// of its last line
+func (p *readmeParser) parseLine(lines []string, i int) int {
+	line := strings.TrimRight(lines[i], " \t")
+	trimmed := strings.TrimLeft(line, " \t")
+	indent := len(line) - len(trimmed)
+
+	if trimmed == "" {
+		// Blank lines end paragraphs; lists may continue after them
+		if len(p.paragraph) > 0 {
+			p.flush()
+		}
+		p.blank = true
+		return i
+	}
+	blank := p.blank
+	p.blank = false
+
+	switch {
+	case indent < 4 && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+		p.flush()
+		fence := trimmed[:3]
+		for i++; i < len(lines); i++ {
+			if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+				break
+			}
+		}
+		p.add(markdownBlock{kind: blockCode})
+
+	case indent < 4 && headingRegex.MatchString(trimmed):
+		p.flush()
+		m := headingRegex.FindStringSubmatch(trimmed)
+		p.sections = append(p.sections, readmeSection{level: len(m[1]), title: plainText(m[2])})
+
+	case len(p.paragraph) > 0 && indent < 4 && setextRegex.MatchString(trimmed):
+		title := strings.Join(p.paragraph, " ")
+		p.paragraph = nil
+		p.flush()
+		level := 1
+		if trimmed[0] == '-' {
+			level = 2
+		}
+		p.sections = append(p.sections, readmeSection{level: level, title: plainText(title)})
+
+	case indent < 4 && thematicBreakRegex.MatchString(trimmed):
+		p.flush()
+
+	case indent < 4 && strings.HasPrefix(trimmed, "<") && (p.list == nil || blank):
+		// HTML blocks run until a blank line; comments until they close
+		p.flush()
+		if strings.HasPrefix(trimmed, "<!--") {
+			for ; i < len(lines) && !strings.Contains(lines[i], "-->"); i++ {
+			}
+		} else {
+			for ; i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != ""; i++ {
+			}
+		}
+		p.add(markdownBlock{kind: blockHTML})
+
+	case indent < 4 && strings.HasPrefix(trimmed, ">"):
+		p.flush()
+		quote := make([]string, 0)
+		for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+			quote = append(quote, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")))
+		}
+		p.add(markdownBlock{kind: blockQuote, text: strings.Join(quote, "\n")})
+		return i - 1
+
+	case indent < 4 && strings.HasPrefix(trimmed, "|"):
+		p.flush()
+		for ; i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "|"); i++ {
+		}
+		p.add(markdownBlock{kind: blockTable})
+
+	case indent >= 4 && len(p.paragraph) == 0 && p.list == nil:
+		// Indented code block
+		for ; i+1 < len(lines); i++ {
+			next := strings.TrimRight(lines[i+1], " \t")
+			if next != "" && len(next)-len(strings.TrimLeft(next, " \t")) < 4 {
+				break
+			}
+		}
+		p.add(markdownBlock{kind: blockCode})
+
+	case listItemRegex.MatchString(trimmed):
+		m := listItemRegex.FindStringSubmatch(trimmed)
+		if p.list != nil && indent >= p.listIndent {
+			p.nested = true
+			return i
+		}
+		ordered := m[1][0] >= '0' && m[1][0] <= '9'
+		if p.list == nil || p.list.ordered != ordered {
+			p.flush()
+			p.list = &markdownBlock{kind: blockList, ordered: ordered}
+		}
+		p.list.items = append(p.list.items, m[3])
+		p.listIndent = indent + len(m[1]) + max(1, len(m[2]))
+		p.nested = false
+
+	case p.list != nil && (indent >= p.listIndent || !blank):
+		// Continuation of the current item, possibly lazy
+		if !p.nested {
+			last := len(p.list.items) - 1
+			p.list.items[last] = strings.TrimSpace(p.list.items[last] + " " + trimmed)
+		}
+
+	default:
+		if p.list != nil {
+			p.flush()
+		}
+		p.paragraph = append(p.paragraph, trimmed)
+	}
+	return i
+}
+
+// flush closes the open paragraph or list
+func (p *readmeParser) flush() {
+	if len(p.paragraph) > 0 {
+		p.add(markdownBlock{kind: blockParagraph, text: strings.Join(p.paragraph, "\n")})
+		p.paragraph = nil
+	}
+	if p.list != nil {
+		list := *p.list
+		p.list = nil
+		p.add(list)
+	}
+}
+
+// add appends a block to the current section
+func (p *readmeParser) add(block markdownBlock) {
+	section := &p.sections[len(p.sections)-1]
+	section.blocks = append(section.blocks, block)
+}
+
+// digestReadme extracts the summary paragraph and the highlights of a README
+func digestReadme(markdown string) readmeDigest {
+	sections := parseReadme(markdown)
+	return readmeDigest{
+		summary:    summaryParagraph(sections),
+		highlights: highlightItems(sections),
+	}
+}
+
+// summaryParagraph returns the first paragraph that reads as prose. Titles,
+// badge rows, image-only paragraphs and short taglines are skipped.
+func summaryParagraph(sections []readmeSection) string {
+	for _, section := range sections {
+		for _, block := range section.blocks {
+			if block.kind != blockParagraph {
+				continue
+			}
+			if len(strings.Fields(plainText(block.text))) >= minSummaryWords {
+				return block.text
+			}
+		}
+	}
+	return ""
+}
+
+// highlightItems returns the items of the first list in a "Features" or
+// "Highlights" section
+func highlightItems(sections []readmeSection) []string {
+	for _, section := range sections {
+		title := strings.ToLower(strings.TrimFunc(section.title, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}))
+		if !highlightTitleRegex.MatchString(title) {
+			continue
+		}
+		for _, block := range section.blocks {
+			if block.kind != blockList {
+				continue
+			}
+			items := make([]string, 0, min(len(block.items), maxHighlights))
+			for _, item := range block.items {
+				item = taskListRegex.ReplaceAllString(item, "")
+				if plainText(item) == "" {
+					continue
+				}
+				items = append(items, item)
+				if len(items) == maxHighlights {
+					break
+				}
+			}
+			return items
+		}
+	}
+	return nil
+}
+
+// Summary returns the plain-text summary, shortened for project cards
+func (d readmeDigest) Summary() string {
+	return truncateText(plainText(d.summary), maxSummaryLength)
+}
+
+// Highlights returns the plain-text highlights
+func (d readmeDigest) Highlights() []string {
+	if len(d.highlights) == 0 {
+		return nil
+	}
+	highlights := make([]string, 0, len(d.highlights))
+	for _, item := range d.highlights {
+		highlights = append(highlights, truncateText(plainText(item), maxHighlightLength))
+	}
+	return highlights
+}
+
+// ExcerptHTML renders the summary paragraph and the highlights. Only p, ul,
+// li, a, strong, em, code and del are emitted and all text is escaped, so
+// README content cannot inject markup. resolve maps link targets to
+// absolute URLs and returns "" for links that should be dropped. Text is
+// shortened like Summary and Highlights; shortened text loses its inline
+// formatting.
+func (d readmeDigest) ExcerptHTML(resolve func(string) string) string {
+	r := inlineRenderer{html: true, resolve: resolve}
+	var b strings.Builder
+	if d.summary != "" {
+		b.WriteString("<p>" + r.renderTruncated(d.summary, maxSummaryLength) + "</p>")
+	}
+	if len(d.highlights) > 0 {
+		b.WriteString("<ul>")
+		for _, item := range d.highlights {
+			b.WriteString("<li>" + r.renderTruncated(item, maxHighlightLength) + "</li>")
+		}
+		b.WriteString("</ul>")
+	}
+	return b.String()
+}
+
+// plainText strips inline markdown and HTML, leaving readable text
+func plainText(markdown string) string {
+	return strings.Join(strings.Fields(inlineRenderer{}.render(markdown)), " ")
+}
+
+// inlineRenderer converts inline markdown to escaped HTML or plain text.
+// Images and raw HTML are dropped in both modes.
+type inlineRenderer struct {
+	html    bool
+	resolve func(string) string
+}
+
+func (r inlineRenderer) render(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range inlineTokenRegex.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(r.text(text[last:m[0]]))
+		last = m[1]
+
+		group := func(n int) (string, bool) {
+			if m[2*n] < 0 {
+				return "", false
+			}
+			return text[m[2*n]:m[2*n+1]], true
+		}
+
+		if code, ok := group(1); ok {
+			b.WriteString(r.wrap("code", r.text(code)))
+		} else if label, ok := group(2); ok {
+			target, _ := group(3)
+			b.WriteString(r.link(target, r.render(label)))
+		} else if target, ok := group(4); ok {
+			b.WriteString(r.link(target, r.text(target)))
+		} else if strong, ok := group(5); ok {
+			b.WriteString(r.wrap("strong", r.render(strong)))
+		} else if strong, ok := group(6); ok {
+			b.WriteString(r.wrap("strong", r.render(strong)))
+		} else if em, ok := group(7); ok {
+			b.WriteString(r.wrap("em", r.render(em)))
+		} else if del, ok := group(8); ok {
+			b.WriteString(r.wrap("del", r.render(del)))
+		}
+		// Images, linked images and raw HTML produce no output
+	}
+	b.WriteString(r.text(text[last:]))
+	return b.String()
+}
+
+// renderTruncated renders text, falling back to the escaped plain text
+// shortened to limit characters when it is longer
+func (r inlineRenderer) renderTruncated(text string, limit int) string {
+	plain := plainText(text)
+	if utf8.RuneCountInString(plain) <= limit {
+		return r.render(text)
+	}
+	return html.EscapeString(truncateText(plain, limit))
+}
+
+// text decodes entities and, in HTML mode, escapes the result
+func (r inlineRenderer) text(s string) string {
+	s = strings.ReplaceAll(html.UnescapeString(s), "\n", " ")
+	if r.html {
+		return html.EscapeString(s)
+	}
+	return s
+}
+
+func (r inlineRenderer) wrap(tag, inner string) string {
+	if !r.html {
+		return inner
+	}
+	return fmt.Sprintf("<%s>%s</%s>", tag, inner, tag)
+}
+
+// link renders an anchor for targets resolve accepts, and the bare label otherwise
+func (r inlineRenderer) link(target, label string) string {
+	if !r.html || r.resolve == nil {
+		return label
+	}
+	href := r.resolve(target)
+	if href == "" {
+		return label
+	}
+	return fmt.Sprintf(`<a href="%s" rel="nofollow noopener noreferrer">%s</a>`, html.EscapeString(href), label)
+}
+
+// truncateText shortens text to at most limit runes, preferably at a word
+// boundary, marking the cut with an ellipsis
+func truncateText(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	cut := string([]rune(text)[:limit-1])
+	if i := strings.LastIndexByte(cut, ' '); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,;:-") + "…"
+}
+
+// resolveReadmeLink turns a README link target into an absolute URL:
+// http(s) links are kept, relative paths point to the file on GitHub and
+// anything else (anchors, mailto:, javascript:) is dropped
+func (g *GitHubScraper) resolveReadmeLink(target string, repo GitHubRepo, baseDir string) string {
+	lower := strings.ToLower(target)
+	switch {
+	case strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://"):
+		return target
+	case target == "" || strings.HasPrefix(target, "#") || strings.HasPrefix(target, "//") || strings.Contains(strings.SplitN(target, "/", 2)[0], ":"):
+		return ""
+	}
+
+	branch := repo.DefaultBranch
+	if branch == "" {
+		branch = "main"
+	}
+	return fmt.Sprintf("https://github.com/%s/%s/blob/%s/%s",
+		g.ownerOf(repo), repo.Name, branch, resolveRepoPath(baseDir, target))
+}
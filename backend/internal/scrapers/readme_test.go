@@ -0,0 +1,221 @@
+package scrapers
+
+import (
+	"html"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+const sampleReadme = `---
+title: Docs site config
+---
+# Homepage [![CI](https://github.com/o/r/actions/workflows/ci.yml/badge.svg)](https://github.com/o/r/actions)
+
+<p align="center">
+  <img src="docs/logo.png">
+</p>
+
+<!-- PORTFOLIO
+featured: true
+-->
+
+![Screenshot](docs/screenshot.png)
+
+A **personal homepage** with a Go backend that aggregates [GitHub](https://github.com),
+Strava and LinkedIn data into a ` + "`SvelteKit`" + ` site. See the [docs](docs/SETUP.md#install).
+
+Second paragraph that is not part of the summary.
+
+## ✨ Key Features
+
+- **Portfolio** from repository markers
+  spanning two lines
+  - nested detail that is dropped
+- [x] Strava <em>stats</em> &amp; heatmaps
+* Responsive images
+
+1. Ordered lists after a bullet list are separate
+
+## Installation
+
+` + "```bash\n# Features\n- not a highlight\n```\n"
+
+func TestParseReadme_Sections(t *testing.T) {
+	sections := parseReadme(sampleReadme)
+
+	titles := make([]string, 0, len(sections))
+	for _, section := range sections {
+		titles = append(titles, section.title)
+	}
+	expected := []string{"", "Homepage", "✨ Key Features", "Installation"}
+	if !reflect.DeepEqual(titles, expected) {
+		t.Fatalf("Expected sections %q, got %q", expected, titles)
+	}
+
+	kinds := make([]markdownBlockKind, 0)
+	for _, block := range sections[1].blocks {
+		kinds = append(kinds, block.kind)
+	}
+	expectedKinds := []markdownBlockKind{blockHTML, blockHTML, blockParagraph, blockParagraph, blockParagraph}
+	if !reflect.DeepEqual(kinds, expectedKinds) {
+		t.Errorf("Expected block kinds %v, got %v", expectedKinds, kinds)
+	}
+
+	features := sections[2].blocks
+	if len(features) != 2 || features[0].kind != blockList || !features[1].ordered {
+		t.Fatalf("Expected a bullet list followed by an ordered list, got %+v", features)
+	}
+	if len(features[0].items) != 3 {
+		t.Errorf("Expected 3 top-level items, got %q", features[0].items)
+	}
+	if features[0].items[0] != "**Portfolio** from repository markers spanning two lines" {
+		t.Errorf("Expected continuation lines to be joined, got %q", features[0].items[0])
+	}
+
+	if blocks := sections[3].blocks; len(blocks) != 1 || blocks[0].kind != blockCode {
+		t.Errorf("Expected the fenced code block to hide its heading, got %+v", blocks)
+	}
+}
+
+func TestReadmeDigest(t *testing.T) {
+	digest := digestReadme(sampleReadme)
+
+	expectedSummary := "A personal homepage with a Go backend that aggregates GitHub, Strava and LinkedIn data into a SvelteKit site. See the docs."
+	if got := digest.Summary(); got != expectedSummary {
+		t.Errorf("Expected summary %q, got %q", expectedSummary, got)
+	}
+
+	expectedHighlights := []string{
+		"Portfolio from repository markers spanning two lines",
+		"Strava stats & heatmaps",
+		"Responsive images",
+	}
+	if got := digest.Highlights(); !reflect.DeepEqual(got, expectedHighlights) {
+		t.Errorf("Expected highlights %q, got %q", expectedHighlights, got)
+	}
+}
+
+func TestReadmeDigest_ExcerptHTML(t *testing.T) {
+	scraper := NewGitHubScraper("o", "", newMockCache())
+	repo := GitHubRepo{Name: "r", DefaultBranch: "main"}
+	resolve := func(target string) string { return scraper.resolveReadmeLink(target, repo, ".") }
+
+	excerpt := digestReadme(sampleReadme).ExcerptHTML(resolve)
+
+	for _, want := range []string{
+		"<p>A <strong>personal homepage</strong> with",
+		`<a href="https://github.com" rel="nofollow noopener noreferrer">GitHub</a>`,
+		"<code>SvelteKit</code>",
+		`<a href="https://github.com/o/r/blob/main/docs/SETUP.md" rel="nofollow noopener noreferrer">docs</a>`,
+		"<li>Strava stats &amp; heatmaps</li>",
+	} {
+		if !strings.Contains(excerpt, want) {
+			t.Errorf("Expected excerpt to contain %q, got %s", want, excerpt)
+		}
+	}
+	if strings.Contains(excerpt, "<em>") || strings.Contains(excerpt, "<img") {
+		t.Errorf("Expected raw HTML to be dropped, got %s", excerpt)
+	}
+}
+
+func TestReadmeDigest_ExcerptHTMLTruncates(t *testing.T) {
+	readme := "A **long** first paragraph " + strings.Repeat("that keeps going & going ", 30) + "until the end.\n"
+
+	excerpt := digestReadme(readme).ExcerptHTML(func(string) string { return "" })
+	if !strings.HasPrefix(excerpt, "<p>A long first paragraph that keeps going &amp; going") || !strings.HasSuffix(excerpt, "…</p>") {
+		t.Errorf("Expected the summary shortened as plain text, got %s", excerpt)
+	}
+	if text := strings.TrimSuffix(strings.TrimPrefix(excerpt, "<p>"), "</p>"); utf8.RuneCountInString(html.UnescapeString(text)) > maxSummaryLength {
+		t.Errorf("Expected at most %d characters, got %d", maxSummaryLength, utf8.RuneCountInString(text))
+	}
+}
+
+func TestReadmeDigest_Sanitizes(t *testing.T) {
+	readme := "Click [here](javascript:alert(1)) or <script>alert(1)</script> to see \"quotes\" & <b>tags</b> in action.\n\n" +
+		"## Features\n\n- [anchor](#top) and *emphasis* with 1 < 2\n"
+
+	excerpt := digestReadme(readme).ExcerptHTML(func(target string) string {
+		return NewGitHubScraper("o", "", newMockCache()).resolveReadmeLink(target, GitHubRepo{Name: "r"}, ".")
+	})
+
+	expected := "<p>Click here or alert(1) to see &#34;quotes&#34; &amp; tags in action.</p>" +
+		"<ul><li>anchor and <em>emphasis</em> with 1 &lt; 2</li></ul>"
+	if excerpt != expected {
+		t.Errorf("Expected %s, got %s", expected, excerpt)
+	}
+}
+
+func TestSummaryParagraph_SkipsTaglines(t *testing.T) {
+	readme := "# Tool\n\n[![Go](badge.svg)](ci)\n\nFast. Simple.\n\nThis tool converts files between formats.\n"
+	if got := digestReadme(readme).Summary(); got != "This tool converts files between formats." {
+		t.Errorf("Unexpected summary %q", got)
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	if got := truncateText("short text", 20); got != "short text" {
+		t.Errorf("Expected text to be kept, got %q", got)
+	}
+	if got := truncateText("one two three four five", 16); got != "one two three…" {
+		t.Errorf("Expected a cut at a word boundary, got %q", got)
+	}
+	if got := truncateText("ääääääääää", 5); got != "ääää…" {
+		t.Errorf("Expected rune-aware truncation, got %q", got)
+	}
+}
+
+func TestResolveReadmeLink(t *testing.T) {
+	scraper := NewGitHubScraper("owner", "", newMockCache())
+	repo := GitHubRepo{Name: "repo", DefaultBranch: "dev"}
+
+	tests := map[string]string{
+		"https://example.com/x": "https://example.com/x",
+		"../LICENSE":            "https://github.com/owner/repo/blob/dev/LICENSE",
+		"guide.md#setup":        "https://github.com/owner/repo/blob/dev/docs/guide.md",
+		"#usage":                "",
+		"mailto:me@example.com": "",
+		"javascript:alert(1)":   "",
+		"//evil.example":        "",
+	}
+	for target, want := range tests {
+		if got := scraper.resolveReadmeLink(target, repo, "docs"); got != want {
+			t.Errorf("resolveReadmeLink(%q): expected %q, got %q", target, want, got)
+		}
+	}
+}
+
+func TestGitHubScraper_ReadmeDigest(t *testing.T) {
+	readme := "# Tool\n<!-- PORTFOLIO -->\n\nConverts **files** between many formats.\n\n## Features\n\n- Fast\n- Small\n"
+	server := newReadmeServer(t, map[string]string{"tool": readme})
+	defer server.Close()
+
+	scraper := NewGitHubScraper("testuser", "token", newMockCache())
+	scraper.client = server.Client()
+	scraper.apiBase = server.URL
+	scraper.SetReadmeExcerpt(true)
+
+	result, err := scraper.Scrape()
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	projects := result.([]Project)
+	if len(projects) != 1 {
+		t.Fatalf("Expected 1 project, got %d", len(projects))
+	}
+
+	project := projects[0]
+	if project.Summary != "Converts files between many formats." {
+		t.Errorf("Unexpected summary %q", project.Summary)
+	}
+	if project.Description != project.Summary {
+		t.Errorf("Expected the summary to replace the missing description, got %q", project.Description)
+	}
+	if !reflect.DeepEqual(project.Highlights, []string{"Fast", "Small"}) {
+		t.Errorf("Unexpected highlights %q", project.Highlights)
+	}
+	if project.ExcerptHTML != "<p>Converts <strong>files</strong> between many formats.</p><ul><li>Fast</li><li>Small</li></ul>" {
+		t.Errorf("Unexpected excerpt %s", project.ExcerptHTML)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -16,24 +17,11 @@ import (
 )
 
 const (
-	stravaAPIBase   = "https://www.strava.com/api/v3"
-	stravaTokenURL  = "https://www.strava.com/oauth/token"
-	cacheKeyStrava  = "strava_data"
-	activityTypeRun = "Run"
+	stravaAPIBase  = "https://www.strava.com/api/v3"
+	stravaTokenURL = "https://www.strava.com/oauth/token"
+	cacheKeyStrava = "strava_data"
 )
 
-// disciplineType maps Strava activity types to a stable discipline key.
-func disciplineType(actType string) string {
-	switch actType {
-	case "Run", "TrailRun", "VirtualRun":
-		return "running"
-	case "Ride", "VirtualRide", "MountainBikeRide", "GravelRide", "EBikeRide", "EMountainBikeRide":
-		return "cycling"
-	default:
-		return "training"
-	}
-}
-
 // StravaScraper implements the Scraper interface for Strava API
 type StravaScraper struct {
 	clientID     string
@@ -44,6 +32,29 @@ type StravaScraper struct {
 	client       *http.Client
 	accessToken  string
 	tokenExpiry  time.Time
+	apiBase      string
+	tokenURL     string
+	// tokenStore persists rotated refresh tokens across runs
+	tokenStore storage.TokenStore
+	// fullSync ignores the activity archive and fetches the whole history
+	fullSync bool
+	// recordDistances are the best efforts personal records are computed for
+	recordDistances []recordDistance
+	// detailRequests bounds the activity detail calls per run
+	detailRequests int
+	// trendMonths and location define the weekly and monthly trends
+	trendMonths int
+	location    *time.Location
+	// routes and heatmap settings; points in privacy zones are dropped
+	routePoints  int
+	routeLimit   int
+	heatmapCell  int
+	privacyZones []PrivacyZone
+	// privacy decides which activities are published and what they reveal
+	privacy        PrivacyRules
+	redactPatterns []*regexp.Regexp
+	// disciplines classifies activity types
+	disciplines *disciplineTaxonomy
 }
 
 // NewStravaScraper creates a new Strava scraper
@@ -57,9 +68,26 @@ func NewStravaScraper(clientID, clientSecret, refreshToken string, cache storage
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		apiBase:         stravaAPIBase,
+		tokenURL:        stravaTokenURL,
+		recordDistances: defaultRecordDistanceList(),
+		detailRequests:  defaultDetailRequests,
+		trendMonths:     defaultTrendMonths,
+		location:        time.UTC,
+		routePoints:     defaultRoutePoints,
+		routeLimit:      defaultRouteLimit,
+		heatmapCell:     defaultHeatmapCell,
+		disciplines:     mustDisciplineTaxonomy(defaultDisciplines),
 	}
 }
 
+// SetFullSync makes the next Scrape fetch the whole activity history
+// instead of only the activities newer than the archive, e.g. to pick up
+// activities that were edited or deleted on Strava
+func (s *StravaScraper) SetFullSync(enabled bool) {
+	s.fullSync = enabled
+}
+
 // Name returns the scraper name
 func (s *StravaScraper) Name() string {
 	return "strava"
@@ -73,22 +101,37 @@ type tokenResponse struct {
 	ExpiresIn    int    `json:"expires_in"`
 }
 
-// stravaActivity represents activity from Strava API
+// stravaActivity represents activity from Strava API. It is also the
+// archive format; bump stravaArchiveVersion when adding fields.
 type stravaActivity struct {
-	ID                 int64   `json:"id"`
-	Name               string  `json:"name"`
-	Distance           float64 `json:"distance"`
-	MovingTime         float64 `json:"moving_time"`
-	ElapsedTime        float64 `json:"elapsed_time"`
-	TotalElevationGain float64 `json:"total_elevation_gain"`
-	Type               string  `json:"type"`
-	StartDate          string  `json:"start_date"`
-	AverageSpeed       float64 `json:"average_speed"`
-	MaxSpeed           float64 `json:"max_speed"`
-	AverageHeartrate   float64 `json:"average_heartrate"`
-	MaxHeartrate       float64 `json:"max_heartrate"`
-	Calories           float64 `json:"calories"`
-	Kilojoules         float64 `json:"kilojoules"`
+	ID                 int64     `json:"id"`
+	Name               string    `json:"name"`
+	Distance           float64   `json:"distance"`
+	MovingTime         float64   `json:"moving_time"`
+	ElapsedTime        float64   `json:"elapsed_time"`
+	TotalElevationGain float64   `json:"total_elevation_gain"`
+	Type               string    `json:"type"` // deprecated by Strava in favour of sport_type
+	SportType          string    `json:"sport_type"`
+	WorkoutType        *int      `json:"workout_type"` // null if never set
+	DeviceName         string    `json:"device_name"`
+	GearID             string    `json:"gear_id"`
+	StartDate          string    `json:"start_date"`
+	AverageSpeed       float64   `json:"average_speed"`
+	MaxSpeed           float64   `json:"max_speed"`
+	AverageHeartrate   float64   `json:"average_heartrate"`
+	MaxHeartrate       float64   `json:"max_heartrate"`
+	Calories           float64   `json:"calories"`
+	Kilojoules         float64   `json:"kilojoules"`
+	Private            bool      `json:"private"`
+	Visibility         string    `json:"visibility"` // "everyone", "followers_only" or "only_me"
+	Commute            bool      `json:"commute"`
+	HideFromHome       bool      `json:"hide_from_home"`
+	AverageWatts       float64   `json:"average_watts"`
+	MaxWatts           float64   `json:"max_watts"` // only set for power meter data
+	StartLatLng        []float64 `json:"start_latlng"`
+	Map                struct {
+		SummaryPolyline string `json:"summary_polyline"`
+	} `json:"map"`
 }
 
 // stravaStats represents athlete stats from Strava API
@@ -140,28 +183,36 @@ func (s *StravaScraper) Scrape() (any, error) {
 
 	// Fetch athlete stats
 	log.Println("Fetching athlete statistics...")
-	stats, err := s.fetchAthleteStats()
+	stats, athlete, err := s.fetchAthleteStats()
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch stats: %w", err)
 	}
 	log.Printf("✓ Stats retrieved: %d total runs, %.2f km total distance", stats.AllRunTotals.Count, stats.AllRunTotals.Distance/1000)
 
-	// Fetch recent running activities (last 30 days, max 200)
-	log.Println("Fetching recent activities (max 200)...")
-	activities, err := s.fetchActivities(200, 1)
+	// Sync the full activity history (incremental after the first run)
+	activities, err := s.syncActivities()
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch activities: %w", err)
 	}
 	log.Printf("✓ Retrieved %d activities", len(activities))
 
+	// Apply privacy rules before any activity is used for published data
+	published := s.applyPrivacy(activities)
+	log.Printf("✓ %d of %d activities may be published", len(published), len(activities))
+	activities = published
+
 	// Filter to running activities only
 	log.Println("Filtering running activities...")
 	runActivities := s.filterRunningActivities(activities)
 	log.Printf("✓ Found %d running activities", len(runActivities))
 
-	// Get recent 10 activities
-	log.Println("Selecting 10 most recent activities...")
-	recentActivities := s.getRecentActivities(runActivities, 10)
+	// Get the most recent runs
+	recentCount := 10
+	if running, ok := s.disciplines.get(disciplineRunning); ok {
+		recentCount = running.Recent
+	}
+	log.Printf("Selecting %d most recent activities...", recentCount)
+	recentActivities := s.getRecentActivities(runActivities, recentCount)
 	log.Printf("✓ Selected %d recent activities", len(recentActivities))
 
 	// Find best activities
@@ -169,16 +220,39 @@ func (s *StravaScraper) Scrape() (any, error) {
 	bestActivities := s.findBestActivities(runActivities)
 	log.Println("✓ Best activities identified")
 
+	// Collect best efforts from activity details for accurate records
+	log.Println("Fetching best efforts of running activities...")
+	efforts, detailCalls := s.syncBestEfforts(activities)
+
 	// Calculate personal records
 	log.Println("Calculating personal records...")
-	personalRecords := s.calculatePersonalRecords(runActivities)
+	personalRecords := s.calculatePersonalRecords(runActivities, efforts)
 	log.Printf("✓ Found %d personal records", len(personalRecords))
 
+	// List races separately
+	races := findRaces(activities)
+	log.Printf("✓ Found %d races", len(races))
+
+	// Collect shoes and bikes with their usage
+	log.Println("Fetching gear...")
+	gear := s.buildGear(athlete, activities, s.detailRequests-detailCalls)
+	log.Printf("✓ Found %d gear items", len(gear))
+
 	// Build per-discipline data from all fetched activities
 	log.Println("Building discipline breakdowns...")
 	disciplines := s.buildDisciplines(activities)
 	log.Printf("✓ Built %d non-running disciplines", len(disciplines))
 
+	// Aggregate training volume per week and month
+	log.Printf("Building trends for the last %d months (%s)...", s.trendMonths, s.location)
+	trends := buildTrends(activities, s.disciplines.disciplineOf, s.trendMonths, s.location, time.Now())
+	log.Printf("✓ Built %d weekly and %d monthly periods", len(trends.Weekly), len(trends.Monthly))
+
+	// Build route maps and the heatmap from summary polylines
+	log.Printf("Building route maps (%d privacy zones)...", len(s.privacyZones))
+	maps := s.buildMaps(activities)
+	log.Printf("✓ Built %d routes and %d heatmap cells", len(maps.Routes.Features), len(maps.Heatmap.Cells))
+
 	// Build result
 	result := models.StravaData{
 		TotalStats: models.StravaStats{
@@ -198,7 +272,11 @@ func (s *StravaScraper) Scrape() (any, error) {
 		RecentActivities: recentActivities,
 		BestActivities:   bestActivities,
 		PersonalRecords:  personalRecords,
+		Races:            races,
+		Gear:             gear,
 		Disciplines:      disciplines,
+		Trends:           trends,
+		Maps:             maps,
 	}
 
 	return result, nil
@@ -224,29 +302,68 @@ func (s *StravaScraper) Refresh() (any, error) {
 	return data, nil
 }
 
-// ensureAccessToken ensures we have a valid access token
+// ensureAccessToken ensures we have a valid access token. The refresh
+// token from the token store is tried first, then the configured one.
 func (s *StravaScraper) ensureAccessToken() error {
 	// Check if we have a valid token
 	if s.accessToken != "" && time.Now().Before(s.tokenExpiry) {
 		return nil
 	}
 
-	// Exchange refresh token for access token
+	var token *tokenResponse
+	var used refreshTokenCandidate
+	var err error
+	candidates := s.refreshTokenCandidates()
+	for i, candidate := range candidates {
+		token, err = s.exchangeToken(candidate.token)
+		if err == nil {
+			used = candidate
+			break
+		}
+		if i < len(candidates)-1 {
+			log.Printf("Warning: refresh token from %s was rejected, trying %s: %v",
+				candidate.source, candidates[i+1].source, err)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	s.accessToken = token.AccessToken
+	s.tokenExpiry = time.Unix(token.ExpiresAt, 0)
+	s.refreshToken = used.token
+
+	// Strava may hand out a new refresh token, after which the old one
+	// stops working eventually
+	if token.RefreshToken != "" && token.RefreshToken != used.token {
+		log.Printf("Strava rotated the refresh token (previous one from %s)", used.source)
+		s.refreshToken = token.RefreshToken
+		s.persistRefreshToken()
+	} else if s.tokenStore != nil && used.source != refreshTokenSourceStore {
+		// Seed the store so later rotations have a starting point
+		s.persistRefreshToken()
+	}
+
+	return nil
+}
+
+// exchangeToken exchanges a refresh token for an access token
+func (s *StravaScraper) exchangeToken(refreshToken string) (*tokenResponse, error) {
 	data := url.Values{}
 	data.Set("client_id", s.clientID)
 	data.Set("client_secret", s.clientSecret)
 	data.Set("grant_type", "refresh_token")
-	data.Set("refresh_token", s.refreshToken)
+	data.Set("refresh_token", refreshToken)
 
-	req, err := http.NewRequest("POST", stravaTokenURL, strings.NewReader(data.Encode()))
+	req, err := http.NewRequest("POST", s.tokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to exchange token: %w", err)
+		return nil, fmt.Errorf("failed to exchange token: %w", err)
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -256,39 +373,31 @@ func (s *StravaScraper) ensureAccessToken() error {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("token exchange failed (status %d): %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("token exchange failed (status %d): %s", resp.StatusCode, string(body))
 	}
 
 	var token tokenResponse
 	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
-		return fmt.Errorf("failed to decode token response: %w", err)
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
 	}
-
-	s.accessToken = token.AccessToken
-	s.tokenExpiry = time.Unix(token.ExpiresAt, 0)
-
-	// Update refresh token if it changed
-	if token.RefreshToken != "" {
-		s.refreshToken = token.RefreshToken
-	}
-
-	return nil
+	return &token, nil
 }
 
-// fetchAthleteStats fetches aggregate statistics
-func (s *StravaScraper) fetchAthleteStats() (*stravaStats, error) {
+// fetchAthleteStats fetches aggregate statistics and returns them with the
+// athlete, whose gear is used by buildGear
+func (s *StravaScraper) fetchAthleteStats() (*stravaStats, *stravaAthlete, error) {
 	// Note: Strava requires athlete ID for stats endpoint
 	// First, get athlete info to get the ID
-	athleteURL := fmt.Sprintf("%s/athlete", stravaAPIBase)
+	athleteURL := fmt.Sprintf("%s/athlete", s.apiBase)
 	req, err := http.NewRequest("GET", athleteURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.accessToken))
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch athlete: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch athlete: %w", err)
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -298,46 +407,48 @@ func (s *StravaScraper) fetchAthleteStats() (*stravaStats, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to fetch athlete (status %d): %s", resp.StatusCode, string(body))
+		return nil, nil, fmt.Errorf("failed to fetch athlete (status %d): %s", resp.StatusCode, string(body))
 	}
 
-	var athlete struct {
-		ID int64 `json:"id"`
-	}
+	var athlete stravaAthlete
 	if err := json.NewDecoder(resp.Body).Decode(&athlete); err != nil {
-		return nil, fmt.Errorf("failed to decode athlete: %w", err)
+		return nil, nil, fmt.Errorf("failed to decode athlete: %w", err)
 	}
 
 	// Now fetch stats
-	statsURL := fmt.Sprintf("%s/athletes/%d/stats", stravaAPIBase, athlete.ID)
+	statsURL := fmt.Sprintf("%s/athletes/%d/stats", s.apiBase, athlete.ID)
 	req, err = http.NewRequest("GET", statsURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.accessToken))
 
 	resp, err = s.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch stats: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch stats: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to fetch stats (status %d): %s", resp.StatusCode, string(body))
+		return nil, nil, fmt.Errorf("failed to fetch stats (status %d): %s", resp.StatusCode, string(body))
 	}
 
 	var stats stravaStats
 	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
-		return nil, fmt.Errorf("failed to decode stats: %w", err)
+		return nil, nil, fmt.Errorf("failed to decode stats: %w", err)
 	}
 
-	return &stats, nil
+	return &stats, &athlete, nil
 }
 
-// fetchActivities fetches activities from Strava
-func (s *StravaScraper) fetchActivities(perPage, page int) ([]stravaActivity, error) {
-	url := fmt.Sprintf("%s/athlete/activities?per_page=%d&page=%d", stravaAPIBase, perPage, page)
+// fetchActivities fetches a page of activities from Strava, limited to
+// activities that started after the given Unix time unless it is 0
+func (s *StravaScraper) fetchActivities(perPage, page int, after int64) ([]stravaActivity, error) {
+	url := fmt.Sprintf("%s/athlete/activities?per_page=%d&page=%d", s.apiBase, perPage, page)
+	if after > 0 {
+		url += fmt.Sprintf("&after=%d", after)
+	}
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -384,7 +495,7 @@ func convertActivity(activity stravaActivity) models.StravaActivity {
 		MovingTime:         int(activity.MovingTime),
 		ElapsedTime:        int(activity.ElapsedTime),
 		TotalElevationGain: activity.TotalElevationGain,
-		Type:               activity.Type,
+		Type:               activity.sportType(),
 		StartDate:          startDate,
 		AveragePace:        averagePace,
 		AverageSpeed:       activity.AverageSpeed,
@@ -392,43 +503,50 @@ func convertActivity(activity stravaActivity) models.StravaActivity {
 		AverageHeartrate:   activity.AverageHeartrate,
 		MaxHeartrate:       activity.MaxHeartrate,
 		Calories:           cal,
+		AverageWatts:       activity.AverageWatts,
+		MaxWatts:           activity.MaxWatts,
+		WorkoutType:        activity.workoutType(),
+		Race:               activity.workoutType() == workoutTypeRace,
+		DeviceName:         activity.DeviceName,
+		GearID:             activity.GearID,
 	}
 }
 
-// filterRunningActivities filters to running activities only
+// filterRunningActivities filters to running activities only, including
+// trail and virtual runs
 func (s *StravaScraper) filterRunningActivities(activities []stravaActivity) []models.StravaActivity {
 	result := make([]models.StravaActivity, 0)
 	for _, activity := range activities {
-		if activity.Type == activityTypeRun {
+		if s.disciplines.disciplineOf(activity.sportType()) == disciplineRunning {
 			result = append(result, convertActivity(activity))
 		}
 	}
 	return result
 }
 
-// buildDisciplines groups all activities into per-discipline summaries.
+// buildDisciplines groups all activities into per-discipline summaries
+// with their bests, in the configured order.
 // Running is excluded — it has its own top-level stats.
 func (s *StravaScraper) buildDisciplines(activities []stravaActivity) []models.StravaDiscipline {
 	type bucket struct {
-		label      string
-		acts       []models.StravaActivity
-		totalTime  int
-		totalDist  float64
-		hrSum      float64
-		hrCount    int
-	}
-
-	buckets := map[string]*bucket{
-		"cycling":  {label: "Cycling & Spinning"},
-		"training": {label: "Training"},
+		acts      []models.StravaActivity
+		totalTime int
+		totalDist float64
+		hrSum     float64
+		hrCount   int
 	}
 
+	buckets := make(map[string]*bucket)
 	for _, raw := range activities {
-		dtype := disciplineType(raw.Type)
-		if dtype == "running" {
-			continue // handled separately
+		dtype := s.disciplines.disciplineOf(raw.sportType())
+		if dtype == "" || dtype == disciplineRunning {
+			continue // not part of any discipline, or handled separately
+		}
+		b, ok := buckets[dtype]
+		if !ok {
+			b = &bucket{}
+			buckets[dtype] = b
 		}
-		b := buckets[dtype]
 		a := convertActivity(raw)
 		b.acts = append(b.acts, a)
 		b.totalTime += a.MovingTime
@@ -439,33 +557,38 @@ func (s *StravaScraper) buildDisciplines(activities []stravaActivity) []models.S
 		}
 	}
 
-	disciplines := make([]models.StravaDiscipline, 0, 2)
-	order := []string{"cycling", "training"}
-	for _, dtype := range order {
-		b := buckets[dtype]
-		if len(b.acts) == 0 {
+	disciplines := make([]models.StravaDiscipline, 0, len(buckets))
+	for _, discipline := range s.disciplines.disciplines {
+		b, ok := buckets[discipline.ID]
+		if !ok {
 			continue
 		}
-		// Sort by date descending, take 5 most recent
+		// Sort by date descending, take the most recent
 		sort.Slice(b.acts, func(i, j int) bool {
 			return b.acts[i].StartDate.After(b.acts[j].StartDate)
 		})
 		recent := b.acts
-		if len(recent) > 5 {
-			recent = recent[:5]
+		if len(recent) > discipline.Recent {
+			recent = recent[:discipline.Recent]
 		}
 		avgHR := 0.0
 		if b.hrCount > 0 {
 			avgHR = b.hrSum / float64(b.hrCount)
 		}
+		totalDist := 0.0
+		if discipline.Distance {
+			totalDist = b.totalDist
+		}
 		disciplines = append(disciplines, models.StravaDiscipline{
-			Type:          dtype,
-			Label:         b.label,
+			Type:          discipline.ID,
+			Label:         discipline.Label,
 			Count:         len(b.acts),
 			TotalTime:     b.totalTime,
-			TotalDistance: b.totalDist,
+			TotalDistance: totalDist,
+			HasDistance:   discipline.Distance,
 			AvgHeartrate:  avgHR,
 			Activities:    recent,
+			Bests:         findBests(b.acts, bestMetricsFor(discipline)),
 		})
 	}
 	return disciplines
@@ -517,47 +640,64 @@ func (s *StravaScraper) findBestActivities(activities []models.StravaActivity) m
 	return best
 }
 
-// calculatePersonalRecords finds PRs for standard distances
-func (s *StravaScraper) calculatePersonalRecords(activities []models.StravaActivity) []models.StravaRecord {
-	// Standard race distances (in meters)
-	distances := map[string]float64{
-		"5k":            5000,
-		"10k":           10000,
-		"half_marathon": 21097.5,
-		"marathon":      42195,
+// calculatePersonalRecords finds the fastest time for each record distance.
+// Best efforts count wherever they happened within a run; activities whose
+// details have not been fetched fall back to matching the whole activity
+// distance within 2%.
+func (s *StravaScraper) calculatePersonalRecords(activities []models.StravaActivity, efforts map[int64][]stravaBestEffort) []models.StravaRecord {
+	foundRecords := make(map[string]models.StravaRecord)
+	consider := func(record models.StravaRecord) {
+		if existing, exists := foundRecords[record.Type]; !exists || record.Time < existing.Time {
+			foundRecords[record.Type] = record
+		}
 	}
 
-	records := make([]models.StravaRecord, 0)
-	foundRecords := make(map[string]*models.StravaRecord)
-
-	// Find fastest time for each distance (within 2% tolerance)
 	for _, activity := range activities {
-		for recordType, targetDistance := range distances {
-			tolerance := targetDistance * 0.02 // 2% tolerance
-			if activity.Distance >= targetDistance-tolerance && activity.Distance <= targetDistance+tolerance {
-				existing, exists := foundRecords[recordType]
-				if !exists || activity.MovingTime < existing.Time {
-					foundRecords[recordType] = &models.StravaRecord{
-						Type:     recordType,
-						Time:     activity.MovingTime,
-						Distance: activity.Distance,
-						Date:     activity.StartDate,
-						Activity: activity,
+		activityEfforts, detailed := efforts[activity.ID]
+		for _, distance := range s.recordDistances {
+			if detailed {
+				for _, effort := range activityEfforts {
+					if recordKey(effort.Name) != distance.key || effort.ElapsedTime <= 0 {
+						continue
+					}
+					date, err := time.Parse(time.RFC3339, s.roundStartDate(effort.StartDate))
+					if err != nil {
+						date = activity.StartDate
 					}
+					consider(models.StravaRecord{
+						Type:     distance.key,
+						Name:     distance.name,
+						Time:     effort.ElapsedTime,
+						Distance: effort.Distance,
+						Date:     date,
+						Source:   recordSourceBestEffort,
+						Activity: activity,
+					})
 				}
+				continue
+			}
+
+			tolerance := distance.meters * 0.02 // 2% tolerance
+			if activity.Distance >= distance.meters-tolerance && activity.Distance <= distance.meters+tolerance {
+				consider(models.StravaRecord{
+					Type:     distance.key,
+					Name:     distance.name,
+					Time:     activity.MovingTime,
+					Distance: activity.Distance,
+					Date:     activity.StartDate,
+					Source:   recordSourceActivity,
+					Activity: activity,
+				})
 			}
 		}
 	}
 
-	// Convert map to slice
-	for _, record := range foundRecords {
-		records = append(records, *record)
+	// Record distances are sorted by length
+	records := make([]models.StravaRecord, 0, len(foundRecords))
+	for _, distance := range s.recordDistances {
+		if record, ok := foundRecords[distance.key]; ok {
+			records = append(records, record)
+		}
 	}
-
-	// Sort by distance
-	sort.Slice(records, func(i, j int) bool {
-		return distances[records[i].Type] < distances[records[j].Type]
-	})
-
 	return records
 }
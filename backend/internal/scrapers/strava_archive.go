@@ -0,0 +1,186 @@
+package scrapers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+const (
+	// cacheKeyStravaArchive stores every activity fetched so far. The
+	// archive is refreshed on every run, so the long TTL only matters when
+	// the generator has not run for a year.
+	cacheKeyStravaArchive = "strava_activity_archive"
+	stravaArchiveTTL      = 365 * 24 * time.Hour
+	// stravaArchiveVersion is the format of archived activities. Incremental
+	// syncs never refetch archived activities, so bump it whenever
+	// stravaActivity gains fields; older archives are then fetched again in
+	// full instead of missing the new fields.
+	stravaArchiveVersion = 1
+
+	// stravaSyncOverlap is how far before the newest archived activity
+	// incremental syncs start, so activities uploaded late (e.g. from a
+	// watch that synced days later) are not missed
+	stravaSyncOverlap = 7 * 24 * time.Hour
+	// stravaRefreshWindow is refetched on every run, so privacy flags
+	// changed on recent activities (e.g. set to private or hidden) are
+	// picked up before the next full sync
+	stravaRefreshWindow = 30 * 24 * time.Hour
+	// stravaFullSyncInterval is how often the whole history is refetched,
+	// which drops deleted activities and picks up changes to older ones
+	stravaFullSyncInterval = 7 * 24 * time.Hour
+
+	// stravaPageSize is the largest page size the activities endpoint allows
+	stravaPageSize = 200
+	// stravaMaxPages guards against endless pagination (200,000 activities)
+	stravaMaxPages = 1000
+)
+
+// stravaArchive is the locally persisted activity history
+type stravaArchive struct {
+	Version    int              `json:"version"`    // stravaArchiveVersion when written; 0 before versioning
+	Activities []stravaActivity `json:"activities"` // newest first
+	UpdatedAt  time.Time        `json:"updated_at"`
+	FullSyncAt time.Time        `json:"full_sync_at"` // last time the whole history was fetched
+}
+
+// latestStart returns the start time of the newest archived activity
+func (a *stravaArchive) latestStart() time.Time {
+	var latest time.Time
+	for _, activity := range a.Activities {
+		if start, err := time.Parse(time.RFC3339, activity.StartDate); err == nil && start.After(latest) {
+			latest = start
+		}
+	}
+	return latest
+}
+
+// merge adds activities to the archive. Activities already archived are
+// replaced, so renamed or edited activities pick up their latest state.
+func (a *stravaArchive) merge(activities []stravaActivity) int {
+	index := make(map[int64]int, len(a.Activities))
+	for i, activity := range a.Activities {
+		index[activity.ID] = i
+	}
+
+	added := 0
+	for _, activity := range activities {
+		if i, ok := index[activity.ID]; ok {
+			a.Activities[i] = activity
+			continue
+		}
+		index[activity.ID] = len(a.Activities)
+		a.Activities = append(a.Activities, activity)
+		added++
+	}
+
+	// RFC 3339 timestamps in UTC sort chronologically as strings
+	sort.SliceStable(a.Activities, func(i, j int) bool {
+		return a.Activities[i].StartDate > a.Activities[j].StartDate
+	})
+	return added
+}
+
+// loadArchive reads the activity archive from the cache. A missing,
+// unreadable or outdated archive yields an empty one, which triggers a full
+// sync.
+func (s *StravaScraper) loadArchive() *stravaArchive {
+	archive := &stravaArchive{}
+	data, err := s.cache.Get(cacheKeyStravaArchive)
+	if err != nil {
+		log.Printf("Warning: failed to read Strava activity archive: %v", err)
+		return archive
+	}
+	if data == nil {
+		return archive
+	}
+	if err := json.Unmarshal(data, archive); err != nil {
+		log.Printf("Warning: ignoring corrupt Strava activity archive: %v", err)
+		return &stravaArchive{}
+	}
+	if archive.Version != stravaArchiveVersion {
+		log.Printf("Strava activity archive has version %d, expected %d; refetching full history", archive.Version, stravaArchiveVersion)
+		return &stravaArchive{}
+	}
+	return archive
+}
+
+// saveArchive writes the activity archive to the cache
+func (s *StravaScraper) saveArchive(archive *stravaArchive) error {
+	archive.Version = stravaArchiveVersion
+	archive.UpdatedAt = time.Now().UTC()
+	data, err := json.Marshal(archive)
+	if err != nil {
+		return fmt.Errorf("failed to marshal activity archive: %w", err)
+	}
+	if err := s.cache.Set(cacheKeyStravaArchive, data, stravaArchiveTTL); err != nil {
+		return fmt.Errorf("failed to save activity archive: %w", err)
+	}
+	return nil
+}
+
+// syncActivities returns the athlete's full activity history. The first run
+// (or a run with SetFullSync, an outdated archive or no full sync within
+// stravaFullSyncInterval) pages through every activity; later runs only
+// fetch activities that started after the newest archived one, less
+// stravaSyncOverlap, and at least those within stravaRefreshWindow.
+func (s *StravaScraper) syncActivities() ([]stravaActivity, error) {
+	archive := &stravaArchive{}
+	if !s.fullSync {
+		archive = s.loadArchive()
+	}
+	if len(archive.Activities) > 0 && time.Since(archive.FullSyncAt) > stravaFullSyncInterval {
+		log.Println("No full sync within a week, fetching full activity history...")
+		archive = &stravaArchive{}
+	}
+
+	var after int64
+	if latest := archive.latestStart(); !latest.IsZero() {
+		// Refetched activities are deduplicated by merge
+		since := latest.Add(-stravaSyncOverlap)
+		if window := time.Now().Add(-stravaRefreshWindow); window.Before(since) {
+			since = window
+		}
+		after = since.Unix()
+		log.Printf("Fetching activities after %s (%d archived)...", since.Format(time.RFC3339), len(archive.Activities))
+	} else {
+		log.Println("No activity archive found, fetching full activity history...")
+	}
+
+	fetched, err := s.fetchAllActivities(after)
+	if err != nil {
+		return nil, err
+	}
+
+	if after == 0 {
+		archive.FullSyncAt = time.Now().UTC()
+	}
+	added := archive.merge(fetched)
+	log.Printf("✓ Activity archive: %d new, %d total", added, len(archive.Activities))
+
+	if err := s.saveArchive(archive); err != nil {
+		// The history is still complete for this run
+		log.Printf("Warning: %v", err)
+	}
+	return archive.Activities, nil
+}
+
+// fetchAllActivities pages through the activities that started after the
+// given Unix time (0 for the full history) until a page comes back short
+func (s *StravaScraper) fetchAllActivities(after int64) ([]stravaActivity, error) {
+	all := make([]stravaActivity, 0)
+	for page := 1; page <= stravaMaxPages; page++ {
+		activities, err := s.fetchActivities(stravaPageSize, page, after)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", page, err)
+		}
+		all = append(all, activities...)
+		if len(activities) < stravaPageSize {
+			return all, nil
+		}
+	}
+	log.Printf("Warning: stopped after %d activity pages (page limit reached)", stravaMaxPages)
+	return all, nil
+}
@@ -0,0 +1,235 @@
+package scrapers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stravaTestStart is the start time of the oldest test activity; activity i
+// starts i hours later
+var stravaTestStart = time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+
+func testStravaActivity(id int64, name string) stravaActivity {
+	return stravaActivity{
+		ID:        id,
+		Name:      name,
+		Type:      "Run",
+		Distance:  5000,
+		StartDate: stravaTestStart.Add(time.Duration(id) * time.Hour).Format(time.RFC3339),
+	}
+}
+
+// stravaTestServer serves the activities endpoint from a fixed history and
+// records the query of every request
+type stravaTestServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	history  []stravaActivity // newest first, like the Strava API
+	requests []string
+}
+
+func newStravaTestServer(t *testing.T, history []stravaActivity) *stravaTestServer {
+	server := &stravaTestServer{history: history}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/athlete/activities" {
+			http.NotFound(w, r)
+			return
+		}
+		server.mu.Lock()
+		server.requests = append(server.requests, r.URL.RawQuery)
+		history := server.history
+		server.mu.Unlock()
+
+		query := r.URL.Query()
+		perPage, _ := strconv.Atoi(query.Get("per_page"))
+		page, _ := strconv.Atoi(query.Get("page"))
+		after, _ := strconv.ParseInt(query.Get("after"), 10, 64)
+
+		matching := make([]stravaActivity, 0)
+		for _, activity := range history {
+			start, _ := time.Parse(time.RFC3339, activity.StartDate)
+			if after == 0 || start.Unix() > after {
+				matching = append(matching, activity)
+			}
+		}
+		from := min((page-1)*perPage, len(matching))
+		to := min(from+perPage, len(matching))
+		if err := json.NewEncoder(w).Encode(matching[from:to]); err != nil {
+			t.Errorf("Failed to write response: %v", err)
+		}
+	}))
+	return server
+}
+
+func newTestStravaScraper(server *stravaTestServer, cache *mockCache) *StravaScraper {
+	scraper := NewStravaScraper("id", "secret", "refresh", cache)
+	scraper.client = server.Client()
+	scraper.apiBase = server.URL
+	scraper.accessToken = "token"
+	scraper.tokenExpiry = time.Now().Add(time.Hour)
+	return scraper
+}
+
+func TestStravaScraper_SyncActivities(t *testing.T) {
+	const count = stravaPageSize + 50
+	history := make([]stravaActivity, 0, count)
+	for id := int64(count); id >= 1; id-- {
+		history = append(history, testStravaActivity(id, fmt.Sprintf("Run %d", id)))
+	}
+	server := newStravaTestServer(t, history)
+	defer server.Close()
+	cache := newMockCache()
+
+	// The first run pages through the whole history
+	activities, err := newTestStravaScraper(server, cache).syncActivities()
+	if err != nil {
+		t.Fatalf("syncActivities failed: %v", err)
+	}
+	if len(activities) != count {
+		t.Fatalf("Expected %d activities, got %d", count, len(activities))
+	}
+	if len(server.requests) != 2 || server.requests[1] != "per_page=200&page=2" {
+		t.Errorf("Expected two full-history pages, got %v", server.requests)
+	}
+
+	// A later run only asks for activities after the newest archived one,
+	// with an overlap for late uploads: this run started before the newest
+	// archived activity but was uploaded after the first sync
+	late := testStravaActivity(count+2, "Late upload")
+	late.StartDate = stravaTestStart.Add((count - 2) * time.Hour).Add(30 * time.Minute).Format(time.RFC3339)
+	server.mu.Lock()
+	server.history = append([]stravaActivity{testStravaActivity(count+1, "New run")}, server.history...)
+	server.history = append(server.history, late)
+	server.requests = nil
+	server.mu.Unlock()
+
+	activities, err = newTestStravaScraper(server, cache).syncActivities()
+	if err != nil {
+		t.Fatalf("syncActivities failed: %v", err)
+	}
+	newest := stravaTestStart.Add(count * time.Hour).Add(-stravaSyncOverlap).Unix()
+	if expected := fmt.Sprintf("per_page=200&page=1&after=%d", newest); len(server.requests) != 1 || server.requests[0] != expected {
+		t.Errorf("Expected a single incremental request %q, got %v", expected, server.requests)
+	}
+	if len(activities) != count+2 || activities[0].Name != "New run" || activities[len(activities)-1].ID != 1 {
+		t.Errorf("Expected the new activity merged in front of the archive, got %d activities starting with %q",
+			len(activities), activities[0].Name)
+	}
+	if activities[3].Name != "Late upload" {
+		t.Errorf("Expected the late upload to be archived in start order, got %q", activities[3].Name)
+	}
+
+	// A full sync ignores the archive
+	server.requests = nil
+	scraper := newTestStravaScraper(server, cache)
+	scraper.SetFullSync(true)
+	if _, err := scraper.syncActivities(); err != nil {
+		t.Fatalf("syncActivities failed: %v", err)
+	}
+	if len(server.requests) != 2 {
+		t.Errorf("Expected a full sync to fetch every page, got %v", server.requests)
+	}
+}
+
+func TestStravaArchive_Merge(t *testing.T) {
+	archive := &stravaArchive{Activities: []stravaActivity{testStravaActivity(2, "Old name"), testStravaActivity(1, "First")}}
+
+	added := archive.merge([]stravaActivity{testStravaActivity(3, "Third"), testStravaActivity(2, "Renamed")})
+	if added != 1 {
+		t.Errorf("Expected 1 new activity, got %d", added)
+	}
+
+	names := make([]string, 0, len(archive.Activities))
+	for _, activity := range archive.Activities {
+		names = append(names, activity.Name)
+	}
+	if fmt.Sprint(names) != "[Third Renamed First]" {
+		t.Errorf("Expected activities newest first with edits applied, got %v", names)
+	}
+	if latest := archive.latestStart(); !latest.Equal(stravaTestStart.Add(3 * time.Hour)) {
+		t.Errorf("Unexpected latest start %v", latest)
+	}
+}
+
+func TestStravaScraper_LoadArchiveCorrupt(t *testing.T) {
+	cache := newMockCache()
+	if err := cache.Set(cacheKeyStravaArchive, []byte("{not json"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	scraper := NewStravaScraper("id", "secret", "refresh", cache)
+	if archive := scraper.loadArchive(); len(archive.Activities) != 0 {
+		t.Errorf("Expected a corrupt archive to be ignored, got %d activities", len(archive.Activities))
+	}
+}
+
+func TestStravaScraper_SyncActivitiesOutdatedArchive(t *testing.T) {
+	// Activities archived before a field was added come back with it set
+	updated := testStravaActivity(2, "Run 2")
+	updated.SportType = "TrailRun"
+	server := newStravaTestServer(t, []stravaActivity{updated, testStravaActivity(1, "Run 1")})
+	defer server.Close()
+
+	cache := newMockCache()
+	outdated, err := json.Marshal(map[string]any{
+		"activities": []stravaActivity{testStravaActivity(2, "Run 2"), testStravaActivity(1, "Run 1")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Set(cacheKeyStravaArchive, outdated, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	activities, err := newTestStravaScraper(server, cache).syncActivities()
+	if err != nil {
+		t.Fatalf("syncActivities failed: %v", err)
+	}
+	if len(server.requests) != 1 || server.requests[0] != "per_page=200&page=1" {
+		t.Errorf("Expected an unversioned archive to trigger a full sync, got %v", server.requests)
+	}
+	if len(activities) != 2 || activities[0].SportType != "TrailRun" {
+		t.Errorf("Expected the refetched activities, got %+v", activities)
+	}
+
+	// The rewritten archive is current, so the next run is incremental
+	if archive := newTestStravaScraper(server, cache).loadArchive(); archive.Version != stravaArchiveVersion || len(archive.Activities) != 2 {
+		t.Errorf("Expected a current archive with two activities, got version %d with %d activities",
+			archive.Version, len(archive.Activities))
+	}
+}
+
+func TestStravaScraper_SyncActivitiesPeriodicFullSync(t *testing.T) {
+	server := newStravaTestServer(t, []stravaActivity{testStravaActivity(2, "Run 2"), testStravaActivity(1, "Run 1")})
+	defer server.Close()
+
+	cache := newMockCache()
+	scraper := newTestStravaScraper(server, cache)
+	deleted := testStravaActivity(3, "Deleted on Strava")
+	archive := &stravaArchive{
+		Activities: []stravaActivity{deleted, testStravaActivity(2, "Run 2"), testStravaActivity(1, "Run 1")},
+		FullSyncAt: time.Now().Add(-stravaFullSyncInterval - time.Hour),
+	}
+	if err := scraper.saveArchive(archive); err != nil {
+		t.Fatal(err)
+	}
+
+	activities, err := scraper.syncActivities()
+	if err != nil {
+		t.Fatalf("syncActivities failed: %v", err)
+	}
+	if len(server.requests) != 1 || server.requests[0] != "per_page=200&page=1" {
+		t.Errorf("Expected a full sync after a week, got %v", server.requests)
+	}
+	if len(activities) != 2 || activities[0].ID != 2 {
+		t.Errorf("Expected the deleted activity to be dropped, got %+v", activities)
+	}
+	if synced := scraper.loadArchive().FullSyncAt; time.Since(synced) > time.Minute {
+		t.Errorf("Expected the full sync time to be recorded, got %v", synced)
+	}
+}
@@ -0,0 +1,117 @@
+package scrapers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mrcodeeu/homepage/internal/models"
+)
+
+// bestMetric ranks the activities of a discipline by one value; the highest
+// value wins
+type bestMetric struct {
+	key         string
+	label       string
+	unit        string  // unit of value: "m", "s", "m/s" or "W"
+	minDistance float64 // meters; shorter activities are not ranked
+	value       func(models.StravaActivity) float64
+}
+
+var (
+	longestDistanceMetric = bestMetric{key: "longest_distance", label: "Longest Distance", unit: "m",
+		value: func(a models.StravaActivity) float64 { return a.Distance }}
+	longestTimeMetric = bestMetric{key: "longest_time", label: "Longest Time", unit: "s",
+		value: func(a models.StravaActivity) float64 { return float64(a.MovingTime) }}
+	mostElevationMetric = bestMetric{key: "most_elevation", label: "Most Elevation", unit: "m",
+		value: func(a models.StravaActivity) float64 { return a.TotalElevationGain }}
+	maxPowerMetric = bestMetric{key: "max_power", label: "Max Power", unit: "W",
+		value: func(a models.StravaActivity) float64 { return a.MaxWatts }}
+)
+
+// fastestMetric ranks activities of at least km kilometers by their average
+// speed. Summary activities carry no splits, so the whole activity counts.
+func fastestMetric(km int) bestMetric {
+	return bestMetric{
+		key:         fmt.Sprintf("fastest_%dk", km),
+		label:       fmt.Sprintf("Fastest %d km+", km),
+		unit:        "m/s",
+		minDistance: float64(km) * 1000,
+		value:       func(a models.StravaActivity) float64 { return a.AverageSpeed },
+	}
+}
+
+// bestMetrics are the metrics a discipline can name besides fastest_<n>k
+var bestMetrics = map[string]bestMetric{
+	longestDistanceMetric.key: longestDistanceMetric,
+	longestTimeMetric.key:     longestTimeMetric,
+	mostElevationMetric.key:   mostElevationMetric,
+	maxPowerMetric.key:        maxPowerMetric,
+}
+
+// parseBestMetric resolves a metric key such as "most_elevation" or
+// "fastest_40k"
+func parseBestMetric(key string) (bestMetric, error) {
+	if metric, ok := bestMetrics[key]; ok {
+		return metric, nil
+	}
+	if distance, ok := strings.CutPrefix(key, "fastest_"); ok {
+		if km, ok := strings.CutSuffix(distance, "k"); ok {
+			if n, err := strconv.Atoi(km); err == nil && n > 0 {
+				return fastestMetric(n), nil
+			}
+		}
+	}
+	return bestMetric{}, fmt.Errorf("unknown best metric %q", key)
+}
+
+// bestMetricsFor returns the metrics of a discipline: the configured ones,
+// otherwise distance, time and elevation for distance sports and time for
+// the others. Running records come from best efforts instead (see
+// calculatePersonalRecords).
+func bestMetricsFor(discipline Discipline) []bestMetric {
+	if len(discipline.Bests) > 0 {
+		metrics := make([]bestMetric, 0, len(discipline.Bests))
+		for _, key := range discipline.Bests {
+			// Keys are validated with the taxonomy
+			if metric, err := parseBestMetric(key); err == nil {
+				metrics = append(metrics, metric)
+			}
+		}
+		return metrics
+	}
+	if discipline.Distance {
+		return []bestMetric{longestDistanceMetric, longestTimeMetric, mostElevationMetric}
+	}
+	return []bestMetric{longestTimeMetric}
+}
+
+// findBests returns the best activity for each metric in metric order.
+// Metrics without any value, e.g. max power without a power meter, are
+// left out.
+func findBests(activities []models.StravaActivity, metrics []bestMetric) []models.StravaBest {
+	bests := make([]models.StravaBest, 0, len(metrics))
+	for _, metric := range metrics {
+		var best *models.StravaActivity
+		bestValue := 0.0
+		for i, activity := range activities {
+			if activity.Distance < metric.minDistance {
+				continue
+			}
+			if value := metric.value(activity); value > bestValue {
+				best, bestValue = &activities[i], value
+			}
+		}
+		if best == nil {
+			continue
+		}
+		bests = append(bests, models.StravaBest{
+			Type:     metric.key,
+			Label:    metric.label,
+			Value:    bestValue,
+			Unit:     metric.unit,
+			Activity: *best,
+		})
+	}
+	return bests
+}
@@ -0,0 +1,109 @@
+package scrapers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mrcodeeu/homepage/internal/models"
+)
+
+// defaultCycling returns the built-in cycling discipline
+func defaultCycling(t *testing.T) Discipline {
+	t.Helper()
+	cycling, ok := mustDisciplineTaxonomy(defaultDisciplines).get("cycling")
+	if !ok {
+		t.Fatal("Expected a built-in cycling discipline")
+	}
+	return cycling
+}
+
+func TestBestMetricsFor(t *testing.T) {
+	keys := func(metrics []bestMetric) string {
+		names := make([]string, 0, len(metrics))
+		for _, metric := range metrics {
+			names = append(names, metric.key)
+		}
+		return strings.Join(names, ",")
+	}
+
+	// Custom disciplines get bests from their configuration, not their ID
+	if got := keys(bestMetricsFor(Discipline{ID: "bike", Distance: true})); got != "longest_distance,longest_time,most_elevation" {
+		t.Errorf("Expected distance bests, got %s", got)
+	}
+	if got := keys(bestMetricsFor(Discipline{ID: "yoga"})); got != "longest_time" {
+		t.Errorf("Expected time bests, got %s", got)
+	}
+	configured := Discipline{ID: "bike", Distance: true, Bests: []string{"fastest_10k", "max_power"}}
+	if got := keys(bestMetricsFor(configured)); got != "fastest_10k,max_power" {
+		t.Errorf("Expected the configured bests, got %s", got)
+	}
+
+	for _, key := range []string{"fastest", "fastest_k", "fastest_0k", "fastest_10", "slowest_10k"} {
+		if _, err := parseBestMetric(key); err == nil {
+			t.Errorf("Expected %q to be rejected", key)
+		}
+	}
+	if _, err := newDisciplineTaxonomy([]Discipline{{ID: "bike", Types: []string{"Ride"}, Bests: []string{"top_speed"}}}); err == nil {
+		t.Error("Expected an unknown best metric to fail validation")
+	}
+}
+
+func TestFindBests_Cycling(t *testing.T) {
+	activities := []models.StravaActivity{
+		{ID: 1, Distance: 25000, MovingTime: 3000, TotalElevationGain: 150, AverageSpeed: 8.3},
+		{ID: 2, Distance: 45000, MovingTime: 6000, TotalElevationGain: 900, AverageSpeed: 7.5, MaxWatts: 650},
+		{ID: 3, Distance: 110000, MovingTime: 15000, TotalElevationGain: 600, AverageSpeed: 7.3},
+		// A short fast ride is not ranked for the 20 km+ metric
+		{ID: 4, Distance: 8000, MovingTime: 900, AverageSpeed: 9.5},
+	}
+
+	bests := make(map[string]models.StravaBest)
+	for _, best := range findBests(activities, bestMetricsFor(defaultCycling(t))) {
+		bests[best.Type] = best
+	}
+
+	expected := map[string]int64{
+		"longest_distance": 3,
+		"longest_time":     3,
+		"most_elevation":   2,
+		"fastest_20k":      1,
+		"fastest_40k":      2,
+		"fastest_100k":     3,
+		"max_power":        2,
+	}
+	for metric, id := range expected {
+		if best, ok := bests[metric]; !ok || best.Activity.ID != id {
+			t.Errorf("Expected activity %d as %s, got %+v", id, metric, best)
+		}
+	}
+	if bests["max_power"].Value != 650 || bests["max_power"].Unit != "W" {
+		t.Errorf("Expected max power of 650 W, got %+v", bests["max_power"])
+	}
+}
+
+func TestFindBests_SkipsMissingValues(t *testing.T) {
+	activities := []models.StravaActivity{{ID: 1, Distance: 30000, MovingTime: 3600, AverageSpeed: 8}}
+
+	for _, best := range findBests(activities, bestMetricsFor(defaultCycling(t))) {
+		switch best.Type {
+		case "max_power", "most_elevation", "fastest_40k", "fastest_100k":
+			t.Errorf("Expected %s to be left out without data, got %+v", best.Type, best)
+		}
+	}
+}
+
+func TestFilterRunningActivities(t *testing.T) {
+	scraper := NewStravaScraper("id", "secret", "refresh", newMockCache())
+	activities := []stravaActivity{
+		{ID: 1, Type: "Run"},
+		{ID: 2, Type: "TrailRun"},
+		{ID: 3, Type: "VirtualRun"},
+		{ID: 4, Type: "Ride"},
+		{ID: 5, Type: "WeightTraining"},
+	}
+
+	runs := scraper.filterRunningActivities(activities)
+	if len(runs) != 3 {
+		t.Errorf("Expected all running types to count as runs, got %d", len(runs))
+	}
+}
@@ -0,0 +1,171 @@
+package scrapers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// disciplineRunning is the discipline behind the top-level running
+	// stats, recent runs, best activities and personal records
+	disciplineRunning = "running"
+	// disciplineCatchAll as the only type matches activities of any type
+	// that no other discipline lists
+	disciplineCatchAll = "*"
+	// defaultDisciplineRecent is the number of recent activities listed
+	// for a discipline that does not set one
+	defaultDisciplineRecent = 5
+)
+
+// Discipline groups Strava activity types into one breakdown
+type Discipline struct {
+	ID       string   `json:"id" yaml:"id" toml:"id"`                   // stable identifier, e.g. "cycling"
+	Label    string   `json:"label" yaml:"label" toml:"label"`          // display name
+	Types    []string `json:"types" yaml:"types" toml:"types"`          // Strava types, or "*" for all others
+	Distance bool     `json:"distance" yaml:"distance" toml:"distance"` // whether distance is totalled and ranked
+	Recent   int      `json:"recent" yaml:"recent" toml:"recent"`       // recent activities listed
+	Bests    []string `json:"bests" yaml:"bests" toml:"bests"`          // best metrics, e.g. "fastest_40k"; derived from Distance if empty
+}
+
+// defaultDisciplines is the taxonomy used unless configured otherwise
+var defaultDisciplines = []Discipline{
+	{ID: disciplineRunning, Label: "Running", Types: []string{"Run", "TrailRun", "VirtualRun"}, Distance: true, Recent: 10},
+	{ID: "cycling", Label: "Cycling & Spinning", Distance: true, Recent: 5,
+		Types: []string{"Ride", "VirtualRide", "MountainBikeRide", "GravelRide", "EBikeRide", "EMountainBikeRide"},
+		Bests: []string{"longest_distance", "longest_time", "most_elevation", "fastest_20k", "fastest_40k", "fastest_100k", "max_power"}},
+	{ID: "training", Label: "Training", Types: []string{disciplineCatchAll}, Recent: 5},
+}
+
+// disciplineTaxonomy classifies activity types into disciplines
+type disciplineTaxonomy struct {
+	disciplines []Discipline
+	byType      map[string]string // lowercase activity type -> discipline ID
+	catchAll    string            // discipline of unlisted types; "" drops them
+}
+
+// newDisciplineTaxonomy validates the disciplines and indexes their types
+func newDisciplineTaxonomy(disciplines []Discipline) (*disciplineTaxonomy, error) {
+	if len(disciplines) == 0 {
+		return nil, fmt.Errorf("no disciplines defined")
+	}
+	taxonomy := &disciplineTaxonomy{byType: make(map[string]string)}
+	seen := make(map[string]bool)
+	for _, discipline := range disciplines {
+		if discipline.ID == "" {
+			return nil, fmt.Errorf("discipline %q has no id", discipline.Label)
+		}
+		if seen[discipline.ID] {
+			return nil, fmt.Errorf("discipline %q is defined twice", discipline.ID)
+		}
+		seen[discipline.ID] = true
+		if len(discipline.Types) == 0 {
+			return nil, fmt.Errorf("discipline %q has no types", discipline.ID)
+		}
+		if discipline.Label == "" {
+			discipline.Label = discipline.ID
+		}
+		if discipline.Recent <= 0 {
+			discipline.Recent = defaultDisciplineRecent
+		}
+		for _, key := range discipline.Bests {
+			if _, err := parseBestMetric(key); err != nil {
+				return nil, fmt.Errorf("discipline %q: %w", discipline.ID, err)
+			}
+		}
+
+		for _, activityType := range discipline.Types {
+			if activityType == disciplineCatchAll {
+				if taxonomy.catchAll != "" {
+					return nil, fmt.Errorf("disciplines %q and %q both match all types", taxonomy.catchAll, discipline.ID)
+				}
+				taxonomy.catchAll = discipline.ID
+				continue
+			}
+			key := strings.ToLower(activityType)
+			if other, ok := taxonomy.byType[key]; ok {
+				return nil, fmt.Errorf("type %q belongs to disciplines %q and %q", activityType, other, discipline.ID)
+			}
+			taxonomy.byType[key] = discipline.ID
+		}
+		taxonomy.disciplines = append(taxonomy.disciplines, discipline)
+	}
+	return taxonomy, nil
+}
+
+// disciplineOf returns the discipline ID of an activity type, or "" if no
+// discipline includes it
+func (t *disciplineTaxonomy) disciplineOf(activityType string) string {
+	if id, ok := t.byType[strings.ToLower(activityType)]; ok {
+		return id
+	}
+	return t.catchAll
+}
+
+// get returns the discipline with the given ID
+func (t *disciplineTaxonomy) get(id string) (Discipline, bool) {
+	for _, discipline := range t.disciplines {
+		if discipline.ID == id {
+			return discipline, true
+		}
+	}
+	return Discipline{}, false
+}
+
+// mustDisciplineTaxonomy builds the built-in taxonomy
+func mustDisciplineTaxonomy(disciplines []Discipline) *disciplineTaxonomy {
+	taxonomy, err := newDisciplineTaxonomy(disciplines)
+	if err != nil {
+		panic(err)
+	}
+	return taxonomy
+}
+
+// LoadDisciplines reads disciplines from a YAML, TOML or JSON file, chosen
+// by extension. The file holds a list under "disciplines".
+func LoadDisciplines(path string) ([]Discipline, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read disciplines: %w", err)
+	}
+
+	var file struct {
+		Disciplines []Discipline `json:"disciplines" yaml:"disciplines" toml:"disciplines"`
+	}
+	switch filepath.Ext(path) {
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(content, &file)
+	case ".toml":
+		err = toml.Unmarshal(content, &file)
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	// Validate early so configuration errors name the file
+	if _, err := newDisciplineTaxonomy(file.Disciplines); err != nil {
+		return nil, fmt.Errorf("invalid disciplines in %s: %w", path, err)
+	}
+	return file.Disciplines, nil
+}
+
+// SetDisciplines replaces the discipline taxonomy. A discipline with the
+// ID "running" feeds the running stats and records.
+func (s *StravaScraper) SetDisciplines(disciplines []Discipline) error {
+	taxonomy, err := newDisciplineTaxonomy(disciplines)
+	if err != nil {
+		return err
+	}
+	s.disciplines = taxonomy
+	return nil
+}
@@ -0,0 +1,123 @@
+package scrapers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDisciplineTaxonomy(t *testing.T) {
+	taxonomy := mustDisciplineTaxonomy(defaultDisciplines)
+
+	tests := map[string]string{
+		"Run":            "running",
+		"trailrun":       "running",
+		"GravelRide":     "cycling",
+		"WeightTraining": "training",
+		"Swim":           "training",
+	}
+	for activityType, expected := range tests {
+		if got := taxonomy.disciplineOf(activityType); got != expected {
+			t.Errorf("disciplineOf(%q) = %q, expected %q", activityType, got, expected)
+		}
+	}
+
+	// Without a catch-all discipline unlisted types belong to none
+	swimOnly := mustDisciplineTaxonomy([]Discipline{{ID: "swimming", Types: []string{"Swim"}}})
+	if got := swimOnly.disciplineOf("Run"); got != "" {
+		t.Errorf("Expected no discipline for an unlisted type, got %q", got)
+	}
+}
+
+func TestNewDisciplineTaxonomy_Invalid(t *testing.T) {
+	tests := map[string][]Discipline{
+		"empty":          {},
+		"missing id":     {{Label: "Swimming", Types: []string{"Swim"}}},
+		"duplicate id":   {{ID: "a", Types: []string{"Swim"}}, {ID: "a", Types: []string{"Hike"}}},
+		"no types":       {{ID: "a"}},
+		"shared type":    {{ID: "a", Types: []string{"Swim"}}, {ID: "b", Types: []string{"swim"}}},
+		"two catch-alls": {{ID: "a", Types: []string{"*"}}, {ID: "b", Types: []string{"*"}}},
+	}
+	for name, disciplines := range tests {
+		if _, err := newDisciplineTaxonomy(disciplines); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoadDisciplines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disciplines.yml")
+	content := `disciplines:
+  - id: running
+    label: Running
+    types: [Run, TrailRun]
+    distance: true
+  - id: swimming
+    label: Swimming
+    types: [Swim]
+    distance: true
+    recent: 3
+  - id: other
+    label: Other
+    types: ["*"]
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	disciplines, err := LoadDisciplines(path)
+	if err != nil {
+		t.Fatalf("LoadDisciplines failed: %v", err)
+	}
+	if len(disciplines) != 3 || disciplines[1].ID != "swimming" || !disciplines[1].Distance || disciplines[1].Recent != 3 {
+		t.Errorf("Unexpected disciplines: %+v", disciplines)
+	}
+
+	invalid := filepath.Join(t.TempDir(), "disciplines.json")
+	if err := os.WriteFile(invalid, []byte(`{"disciplines": [{"id": "a"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDisciplines(invalid); err == nil {
+		t.Error("Expected a discipline without types to be rejected")
+	}
+}
+
+func TestStravaScraper_BuildDisciplines(t *testing.T) {
+	scraper := NewStravaScraper("id", "secret", "refresh", newMockCache())
+	if err := scraper.SetDisciplines([]Discipline{
+		{ID: "running", Types: []string{"Run"}, Distance: true},
+		{ID: "swimming", Label: "Swimming", Types: []string{"Swim"}, Distance: true, Recent: 1},
+		{ID: "strength", Label: "Strength", Types: []string{"WeightTraining"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	activities := []stravaActivity{
+		{ID: 1, Type: "Run", Distance: 5000, StartDate: "2024-05-01T07:00:00Z"},
+		{ID: 2, Type: "Swim", Distance: 1500, StartDate: "2024-05-02T07:00:00Z"},
+		{ID: 3, Type: "Swim", Distance: 2000, StartDate: "2024-05-03T07:00:00Z"},
+		{ID: 4, Type: "WeightTraining", Distance: 10, MovingTime: 3600, StartDate: "2024-05-04T07:00:00Z"},
+		{ID: 5, Type: "Yoga", StartDate: "2024-05-05T07:00:00Z"},
+	}
+
+	disciplines := scraper.buildDisciplines(activities)
+	if len(disciplines) != 2 {
+		t.Fatalf("Expected swimming and strength, got %+v", disciplines)
+	}
+
+	swimming := disciplines[0]
+	if swimming.Type != "swimming" || swimming.Label != "Swimming" || swimming.Count != 2 || swimming.TotalDistance != 3500 {
+		t.Errorf("Unexpected swimming breakdown: %+v", swimming)
+	}
+	if len(swimming.Activities) != 1 || swimming.Activities[0].ID != 3 {
+		t.Errorf("Expected only the most recent swim, got %+v", swimming.Activities)
+	}
+
+	strength := disciplines[1]
+	if strength.HasDistance || strength.TotalDistance != 0 {
+		t.Errorf("Expected no distance for strength training, got %+v", strength)
+	}
+	if len(strength.Bests) != 1 || strength.Bests[0].Type != "longest_time" {
+		t.Errorf("Expected only the longest session as best, got %+v", strength.Bests)
+	}
+}
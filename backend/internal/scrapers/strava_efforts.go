@@ -0,0 +1,273 @@
+package scrapers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// cacheKeyStravaEfforts stores the best efforts of every activity whose
+	// details were fetched. Best efforts of an activity do not change, so
+	// each activity is only fetched once.
+	cacheKeyStravaEfforts = "strava_best_efforts"
+	stravaEffortsTTL      = 365 * 24 * time.Hour
+
+	// defaultDetailRequests bounds the detail calls per run; Strava allows
+	// 100 requests per 15 minutes, and the remaining activities are
+	// fetched on the next runs
+	defaultDetailRequests = 50
+
+	// Sources of a personal record
+	recordSourceBestEffort = "best_effort"
+	recordSourceActivity   = "activity"
+)
+
+// bestEffortDistances are the best effort names Strava reports for runs,
+// with their distance in meters
+var bestEffortDistances = map[string]float64{
+	"400m":          400,
+	"1/2 mile":      804.672,
+	"1k":            1000,
+	"1 mile":        1609.344,
+	"2 mile":        3218.688,
+	"5k":            5000,
+	"10k":           10000,
+	"15k":           15000,
+	"10 mile":       16093.44,
+	"20k":           20000,
+	"Half-Marathon": 21097.5,
+	"30k":           30000,
+	"Marathon":      42195,
+	"50k":           50000,
+}
+
+// defaultRecordDistances are the best efforts turned into personal records
+// unless configured otherwise
+var defaultRecordDistances = []string{"400m", "1k", "1 mile", "5k", "10k", "Half-Marathon", "Marathon"}
+
+// defaultRecordDistanceList returns the record distances used unless
+// SetRecordDistances is called
+func defaultRecordDistanceList() []recordDistance {
+	distances, err := parseRecordDistances(defaultRecordDistances)
+	if err != nil {
+		panic(err)
+	}
+	return distances
+}
+
+// recordDistance is a distance personal records are computed for
+type recordDistance struct {
+	name   string  // Strava best effort name, e.g. "Half-Marathon"
+	key    string  // record type, e.g. "half_marathon"
+	meters float64 // distance in meters
+}
+
+// recordKey converts a best effort name into a record type: "1 mile"
+// becomes "1_mile" and "Half-Marathon" becomes "half_marathon"
+func recordKey(name string) string {
+	return strings.NewReplacer(" ", "_", "-", "_", "/", "_").Replace(strings.ToLower(name))
+}
+
+// parseRecordDistances resolves best effort names (case-insensitive, or as
+// record types like "half_marathon") into record distances
+func parseRecordDistances(names []string) ([]recordDistance, error) {
+	distances := make([]recordDistance, 0, len(names))
+	seen := make(map[string]bool)
+	for _, name := range names {
+		var match *recordDistance
+		for effort, meters := range bestEffortDistances {
+			if strings.EqualFold(effort, name) || recordKey(effort) == recordKey(name) {
+				match = &recordDistance{name: effort, key: recordKey(effort), meters: meters}
+				break
+			}
+		}
+		if match == nil {
+			known := make([]string, 0, len(bestEffortDistances))
+			for effort := range bestEffortDistances {
+				known = append(known, effort)
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("unknown personal record distance %q (valid: %s)", name, strings.Join(known, ", "))
+		}
+		if !seen[match.key] {
+			seen[match.key] = true
+			distances = append(distances, *match)
+		}
+	}
+	sort.Slice(distances, func(i, j int) bool { return distances[i].meters < distances[j].meters })
+	return distances, nil
+}
+
+// stravaBestEffort is a best effort of a detailed Strava activity
+type stravaBestEffort struct {
+	Name        string  `json:"name"`
+	Distance    float64 `json:"distance"`
+	ElapsedTime int     `json:"elapsed_time"`
+	MovingTime  int     `json:"moving_time"`
+	StartDate   string  `json:"start_date"`
+}
+
+// SetRecordDistances sets the best efforts personal records are computed
+// for, e.g. "5k" or "1 mile"
+func (s *StravaScraper) SetRecordDistances(names []string) error {
+	distances, err := parseRecordDistances(names)
+	if err != nil {
+		return err
+	}
+	s.recordDistances = distances
+	return nil
+}
+
+// SetDetailRequestLimit sets how many activity details are fetched per run
+// to collect best efforts; 0 disables detail calls, so records come from
+// whole activities only
+func (s *StravaScraper) SetDetailRequestLimit(limit int) {
+	s.detailRequests = max(limit, 0)
+}
+
+// loadBestEfforts returns the cached best efforts by activity ID
+func (s *StravaScraper) loadBestEfforts() map[int64][]stravaBestEffort {
+	efforts := make(map[int64][]stravaBestEffort)
+	data, err := s.cache.Get(cacheKeyStravaEfforts)
+	if err != nil {
+		log.Printf("Warning: failed to read cached best efforts: %v", err)
+		return efforts
+	}
+	if data != nil {
+		if err := json.Unmarshal(data, &efforts); err != nil {
+			log.Printf("Warning: ignoring corrupt best effort cache: %v", err)
+			return make(map[int64][]stravaBestEffort)
+		}
+	}
+	return efforts
+}
+
+// syncBestEfforts returns the best efforts of the running activities,
+// fetching the details of up to the configured number of activities that
+// have not been fetched yet (newest first), and the number of detail calls
+// made. Activities without details are missing from the result; activities
+// deleted on Strava count as having no best efforts.
+func (s *StravaScraper) syncBestEfforts(activities []stravaActivity) (map[int64][]stravaBestEffort, int) {
+	efforts := s.loadBestEfforts()
+
+	pending := make([]stravaActivity, 0)
+	for _, activity := range activities {
+		if s.disciplines.disciplineOf(activity.sportType()) != disciplineRunning {
+			continue
+		}
+		if _, ok := efforts[activity.ID]; !ok {
+			pending = append(pending, activity)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].StartDate > pending[j].StartDate })
+
+	fetched, calls := 0, 0
+	for _, activity := range pending {
+		if calls >= s.detailRequests {
+			break
+		}
+		calls++
+		activityEfforts, err := s.fetchBestEfforts(activity.ID)
+		if err != nil {
+			if stopsDetailRequests(err) {
+				log.Printf("Warning: stopped fetching activity details: %v", err)
+				break
+			}
+			log.Printf("Warning: skipping activity details: %v", err)
+			continue
+		}
+		efforts[activity.ID] = activityEfforts
+		fetched++
+	}
+
+	if fetched > 0 {
+		data, err := json.Marshal(efforts)
+		if err == nil {
+			err = s.cache.Set(cacheKeyStravaEfforts, data, stravaEffortsTTL)
+		}
+		if err != nil {
+			log.Printf("Warning: failed to cache best efforts: %v", err)
+		}
+	}
+	if missing := len(pending) - fetched; missing > 0 {
+		log.Printf("Best efforts: %d activities fetched, %d left for later runs (records use whole activities for those)",
+			fetched, missing)
+	} else {
+		log.Printf("Best efforts: %d activities fetched, all running activities covered", fetched)
+	}
+	return efforts, calls
+}
+
+// stravaStatusError is an unexpected status of a Strava API response
+type stravaStatusError struct {
+	resource string
+	status   int
+	body     string
+}
+
+func (e *stravaStatusError) Error() string {
+	return fmt.Sprintf("failed to fetch %s (status %d): %s", e.resource, e.status, e.body)
+}
+
+// stopsDetailRequests reports whether an error affects every further call
+// as well: missing authorization, missing scopes and rate limits
+func stopsDetailRequests(err error) bool {
+	var statusErr *stravaStatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	switch statusErr.status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return true
+	default:
+		return false
+	}
+}
+
+// fetchBestEfforts fetches the detailed activity and returns its best efforts
+func (s *StravaScraper) fetchBestEfforts(activityID int64) ([]stravaBestEffort, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/activities/%d", s.apiBase, activityID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.accessToken))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch activity %d: %w", activityID, err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Printf("Warning: failed to close response body: %v", closeErr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		// Deleted activities stay archived; remember them as without best
+		// efforts so they are not requested again
+		return make([]stravaBestEffort, 0), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &stravaStatusError{resource: fmt.Sprintf("activity %d", activityID), status: resp.StatusCode, body: string(body)}
+	}
+
+	var detail struct {
+		BestEfforts []stravaBestEffort `json:"best_efforts"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&detail); err != nil {
+		return nil, fmt.Errorf("failed to decode activity %d: %w", activityID, err)
+	}
+	if detail.BestEfforts == nil {
+		// Remember that the activity has no best efforts
+		detail.BestEfforts = make([]stravaBestEffort, 0)
+	}
+	return detail.BestEfforts, nil
+}
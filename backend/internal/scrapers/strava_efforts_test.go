@@ -0,0 +1,168 @@
+package scrapers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mrcodeeu/homepage/internal/models"
+)
+
+func TestParseRecordDistances(t *testing.T) {
+	distances, err := parseRecordDistances([]string{"marathon", "5K", "half_marathon", "5k"})
+	if err != nil {
+		t.Fatalf("parseRecordDistances failed: %v", err)
+	}
+
+	keys := make([]string, 0, len(distances))
+	for _, distance := range distances {
+		keys = append(keys, distance.key)
+	}
+	if got := strings.Join(keys, ","); got != "5k,half_marathon,marathon" {
+		t.Errorf("Expected distances deduplicated and sorted by length, got %s", got)
+	}
+	if distances[1].name != "Half-Marathon" {
+		t.Errorf("Expected the Strava best effort name, got %q", distances[1].name)
+	}
+
+	if _, err := parseRecordDistances([]string{"7k"}); err == nil {
+		t.Error("Expected an unknown distance to fail")
+	}
+}
+
+func TestStravaScraper_SyncBestEfforts(t *testing.T) {
+	var mu sync.Mutex
+	var fetched []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetched = append(fetched, r.URL.Path)
+		mu.Unlock()
+		if _, err := fmt.Fprint(w, `{"best_efforts": [{"name": "5k", "distance": 5000, "elapsed_time": 1500}]}`); err != nil {
+			t.Errorf("Failed to write response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	cache := newMockCache()
+	scraper := newTestStravaScraper(&stravaTestServer{Server: server}, cache)
+	scraper.SetDetailRequestLimit(2)
+
+	ride := testStravaActivity(4, "Ride")
+	ride.Type = "Ride"
+	activities := []stravaActivity{testStravaActivity(1, "Old"), testStravaActivity(2, "Middle"), testStravaActivity(3, "New"), ride}
+
+	// The newest runs are fetched first; rides have no best efforts
+	efforts, calls := scraper.syncBestEfforts(activities)
+	if len(fetched) != 2 || fetched[0] != "/activities/3" || fetched[1] != "/activities/2" {
+		t.Errorf("Expected the two newest runs to be fetched, got %v", fetched)
+	}
+	if calls != 2 || len(efforts) != 2 || len(efforts[3]) != 1 {
+		t.Errorf("Expected best efforts of two activities, got %v", efforts)
+	}
+
+	// Cached activities are not fetched again
+	fetched = nil
+	efforts, _ = scraper.syncBestEfforts(activities)
+	if len(fetched) != 1 || fetched[0] != "/activities/1" {
+		t.Errorf("Expected only the remaining run to be fetched, got %v", fetched)
+	}
+	if len(efforts) != 3 {
+		t.Errorf("Expected best efforts of all runs, got %d", len(efforts))
+	}
+}
+
+func TestStravaScraper_SyncBestEffortsErrors(t *testing.T) {
+	var mu sync.Mutex
+	var fetched []string
+	statuses := map[string]int{"/activities/3": http.StatusNotFound, "/activities/2": http.StatusInternalServerError}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetched = append(fetched, r.URL.Path)
+		status, ok := statuses[r.URL.Path]
+		mu.Unlock()
+		if ok {
+			http.Error(w, `{"message": "error"}`, status)
+			return
+		}
+		if _, err := fmt.Fprint(w, `{"best_efforts": [{"name": "5k", "distance": 5000, "elapsed_time": 1500}]}`); err != nil {
+			t.Errorf("Failed to write response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	activities := []stravaActivity{testStravaActivity(1, "Old"), testStravaActivity(2, "Middle"), testStravaActivity(3, "Deleted")}
+	scraper := newTestStravaScraper(&stravaTestServer{Server: server}, newMockCache())
+
+	// A deleted run has no best efforts; other failures skip only that run
+	efforts, calls := scraper.syncBestEfforts(activities)
+	if calls != 3 || len(fetched) != 3 {
+		t.Errorf("Expected all three runs to be requested, got %v", fetched)
+	}
+	if deleted, ok := efforts[3]; !ok || len(deleted) != 0 {
+		t.Errorf("Expected the deleted run to be cached without best efforts, got %v", efforts)
+	}
+	if _, ok := efforts[2]; ok || len(efforts[1]) != 1 {
+		t.Errorf("Expected the failed run to be retried later and the oldest fetched, got %v", efforts)
+	}
+
+	// Rate limits stop the run
+	mu.Lock()
+	fetched = nil
+	statuses["/activities/2"] = http.StatusTooManyRequests
+	mu.Unlock()
+	if _, calls := scraper.syncBestEfforts(append(activities, testStravaActivity(0, "Oldest"))); calls != 1 || len(fetched) != 1 {
+		t.Errorf("Expected a rate limit to stop fetching, got %v", fetched)
+	}
+}
+
+func TestStravaScraper_CalculatePersonalRecords(t *testing.T) {
+	scraper := NewStravaScraper("id", "secret", "refresh", newMockCache())
+	if err := scraper.SetRecordDistances([]string{"5k", "10k"}); err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	activities := []models.StravaActivity{
+		// A detailed long run whose 5k best effort is the fastest
+		{ID: 1, Distance: 15000, MovingTime: 4500, StartDate: day},
+		// A 5k run without details, only matched as a whole activity
+		{ID: 2, Distance: 5020, MovingTime: 1380, StartDate: day.AddDate(0, 0, 1)},
+		// A detailed 10k run; the whole activity time is ignored in favour of the effort
+		{ID: 3, Distance: 10000, MovingTime: 2900, StartDate: day.AddDate(0, 0, 2)},
+	}
+	efforts := map[int64][]stravaBestEffort{
+		1: {
+			{Name: "5k", Distance: 5000, ElapsedTime: 1320, StartDate: "2024-05-01T08:20:00Z"},
+			{Name: "10k", Distance: 10000, ElapsedTime: 2950},
+		},
+		3: {{Name: "10k", Distance: 10000, ElapsedTime: 2930}},
+	}
+
+	records := scraper.calculatePersonalRecords(activities, efforts)
+	if len(records) != 2 {
+		t.Fatalf("Expected two records, got %d", len(records))
+	}
+
+	fiveK := records[0]
+	if fiveK.Type != "5k" || fiveK.Time != 1320 || fiveK.Activity.ID != 1 || fiveK.Source != recordSourceBestEffort {
+		t.Errorf("Expected the best effort within the long run, got %+v", fiveK)
+	}
+	if !fiveK.Date.Equal(time.Date(2024, 5, 1, 8, 20, 0, 0, time.UTC)) {
+		t.Errorf("Expected the effort start as record date, got %v", fiveK.Date)
+	}
+
+	tenK := records[1]
+	if tenK.Type != "10k" || tenK.Time != 2930 || tenK.Activity.ID != 3 {
+		t.Errorf("Expected the faster 10k effort, got %+v", tenK)
+	}
+
+	// Without best efforts the whole-activity heuristic is used
+	records = scraper.calculatePersonalRecords(activities, nil)
+	if len(records) != 2 || records[0].Activity.ID != 2 || records[0].Source != recordSourceActivity {
+		t.Errorf("Expected whole-activity records without best efforts, got %+v", records)
+	}
+}
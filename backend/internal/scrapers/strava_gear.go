@@ -0,0 +1,229 @@
+package scrapers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mrcodeeu/homepage/internal/models"
+)
+
+const (
+	// cacheKeyStravaGear stores gear details by gear ID. Brand, model and
+	// the retired flag rarely change, so details are refreshed weekly.
+	cacheKeyStravaGear = "strava_gear"
+	stravaGearTTL      = 365 * 24 * time.Hour
+	stravaGearRefresh  = 7 * 24 * time.Hour
+
+	// Gear kinds as published
+	gearKindShoes = "shoes"
+	gearKindBike  = "bike"
+)
+
+// stravaGear is gear as listed on the athlete or returned by /gear/{id}
+type stravaGear struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Primary   bool    `json:"primary"`
+	Retired   bool    `json:"retired"`
+	Distance  float64 `json:"distance"` // meters, as tracked by Strava
+	BrandName string  `json:"brand_name"`
+	ModelName string  `json:"model_name"`
+}
+
+// stravaAthlete is the authenticated athlete; shoes and bikes are only
+// listed with the profile:read_all scope
+type stravaAthlete struct {
+	ID    int64        `json:"id"`
+	Shoes []stravaGear `json:"shoes"`
+	Bikes []stravaGear `json:"bikes"`
+}
+
+// cachedGear is gear details as cached between runs
+type cachedGear struct {
+	Gear      stravaGear `json:"gear"`
+	FetchedAt time.Time  `json:"fetched_at"`
+}
+
+// getJSON fetches an API path and decodes the JSON response into out
+func (s *StravaScraper) getJSON(path string, out any) error {
+	req, err := http.NewRequest("GET", s.apiBase+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.accessToken))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", path, err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Printf("Warning: failed to close response body: %v", closeErr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to fetch %s (status %d): %s", path, resp.StatusCode, string(body))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// gearUsage is the usage of one gear in the published activities
+type gearUsage struct {
+	count      int
+	distance   float64
+	movingTime int
+	lastUsed   time.Time
+}
+
+// loadGear returns the cached gear details by gear ID
+func (s *StravaScraper) loadGear() map[string]cachedGear {
+	gear := make(map[string]cachedGear)
+	data, err := s.cache.Get(cacheKeyStravaGear)
+	if err != nil {
+		log.Printf("Warning: failed to read cached gear: %v", err)
+		return gear
+	}
+	if data != nil {
+		if err := json.Unmarshal(data, &gear); err != nil {
+			log.Printf("Warning: ignoring corrupt gear cache: %v", err)
+			return make(map[string]cachedGear)
+		}
+	}
+	return gear
+}
+
+// buildGear lists the given athlete's shoes and bikes with the usage of each in
+// the given activities. Gear only referenced by activities, e.g. retired
+// gear Strava no longer lists on the athlete, is included as well. Details
+// are cached and at most budget of them are fetched per run, sharing the
+// detail request limit with best efforts; gear without any details yet is
+// left out until a later run. Failing requests are logged, so gear never
+// fails the whole scrape.
+func (s *StravaScraper) buildGear(athlete *stravaAthlete, activities []stravaActivity, budget int) []models.StravaGear {
+	if athlete == nil {
+		athlete = &stravaAthlete{}
+	}
+
+	kinds := make(map[string]string)
+	listed := make(map[string]stravaGear)
+	ids := make([]string, 0, len(athlete.Shoes)+len(athlete.Bikes))
+	for _, gear := range athlete.Shoes {
+		kinds[gear.ID] = gearKindShoes
+		listed[gear.ID] = gear
+		ids = append(ids, gear.ID)
+	}
+	for _, gear := range athlete.Bikes {
+		kinds[gear.ID] = gearKindBike
+		listed[gear.ID] = gear
+		ids = append(ids, gear.ID)
+	}
+
+	usage := make(map[string]*gearUsage)
+	for _, activity := range activities {
+		if activity.GearID == "" {
+			continue
+		}
+		if _, ok := kinds[activity.GearID]; !ok {
+			// Bike IDs start with "b", shoe IDs with "g"
+			kinds[activity.GearID] = gearKindShoes
+			if strings.HasPrefix(activity.GearID, "b") {
+				kinds[activity.GearID] = gearKindBike
+			}
+			ids = append(ids, activity.GearID)
+		}
+		u, ok := usage[activity.GearID]
+		if !ok {
+			u = &gearUsage{}
+			usage[activity.GearID] = u
+		}
+		converted := convertActivity(activity)
+		u.count++
+		u.distance += converted.Distance
+		u.movingTime += converted.MovingTime
+		if converted.StartDate.After(u.lastUsed) {
+			u.lastUsed = converted.StartDate
+		}
+	}
+
+	// The athlete only lists summaries; details add brand, model and the
+	// retired flag, and cover gear the athlete no longer lists
+	cached := s.loadGear()
+	fetched := 0
+	for _, id := range ids {
+		if entry, ok := cached[id]; ok && time.Since(entry.FetchedAt) < stravaGearRefresh {
+			continue
+		}
+		if fetched >= budget {
+			log.Printf("Gear: detail request limit reached, remaining gear is fetched on later runs")
+			break
+		}
+		fetched++
+		var gear stravaGear
+		if err := s.getJSON("/gear/"+id, &gear); err != nil {
+			log.Printf("Warning: failed to fetch gear %s: %v", id, err)
+			continue
+		}
+		cached[id] = cachedGear{Gear: gear, FetchedAt: time.Now().UTC()}
+	}
+	if fetched > 0 {
+		data, err := json.Marshal(cached)
+		if err == nil {
+			err = s.cache.Set(cacheKeyStravaGear, data, stravaGearTTL)
+		}
+		if err != nil {
+			log.Printf("Warning: failed to cache gear: %v", err)
+		}
+	}
+
+	result := make([]models.StravaGear, 0, len(ids))
+	for _, id := range ids {
+		entry, hasDetails := cached[id]
+		summary, isListed := listed[id]
+		if !hasDetails && !isListed {
+			continue
+		}
+		gear := entry.Gear
+		if isListed {
+			// Summaries carry the current name, primary flag and distance
+			gear.ID, gear.Name, gear.Primary, gear.Distance = summary.ID, summary.Name, summary.Primary, summary.Distance
+		}
+		item := models.StravaGear{
+			ID:       gear.ID,
+			Name:     gear.Name,
+			Kind:     kinds[id],
+			Brand:    gear.BrandName,
+			Model:    gear.ModelName,
+			Primary:  gear.Primary,
+			Retired:  gear.Retired,
+			Distance: gear.Distance,
+		}
+		if u, ok := usage[id]; ok {
+			lastUsed := u.lastUsed
+			item.ActivityCount = u.count
+			item.ActivityDistance = u.distance
+			item.MovingTime = u.movingTime
+			item.LastUsed = &lastUsed
+		}
+		result = append(result, item)
+	}
+
+	// Active gear first, then by distance
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Retired != result[j].Retired {
+			return !result[i].Retired
+		}
+		return result[i].Distance > result[j].Distance
+	})
+	return result
+}
@@ -0,0 +1,132 @@
+package scrapers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestStravaScraper_BuildGear(t *testing.T) {
+	details := map[string]string{
+		"g1": `{"id": "g1", "name": "Daily trainer", "primary": true, "distance": 400000, "brand_name": "Acme", "model_name": "Runner 3"}`,
+		"b1": `{"id": "b1", "name": "Gravel bike", "distance": 2500000}`,
+		"g9": `{"id": "g9", "name": "Old racer", "retired": true, "distance": 900000}`,
+	}
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		detail, ok := details[strings.TrimPrefix(r.URL.Path, "/gear/")]
+		if !strings.HasPrefix(r.URL.Path, "/gear/") || !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if _, err := fmt.Fprint(w, detail); err != nil {
+			t.Errorf("Failed to write response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	cache := newMockCache()
+	scraper := newTestStravaScraper(&stravaTestServer{Server: server}, cache)
+
+	first := testStravaActivity(1, "Easy")
+	first.GearID = "g1"
+	second := testStravaActivity(2, "Tempo")
+	second.GearID = "g1"
+	// Retired shoes are only known from activities
+	retired := testStravaActivity(3, "Race")
+	retired.GearID = "g9"
+	// Gear the API no longer returns is left out
+	deleted := testStravaActivity(4, "Deleted gear")
+	deleted.GearID = "g404"
+
+	athlete := &stravaAthlete{
+		ID:    1,
+		Shoes: []stravaGear{{ID: "g1", Name: "Daily trainer", Primary: true, Distance: 400000}},
+		Bikes: []stravaGear{{ID: "b1", Name: "Gravel bike", Distance: 2500000}},
+	}
+	activities := []stravaActivity{first, second, retired, deleted, testStravaActivity(5, "No gear")}
+	gear := scraper.buildGear(athlete, activities, 10)
+	if len(gear) != 3 {
+		t.Fatalf("Expected three gear items, got %+v", gear)
+	}
+
+	// Active gear by distance, then retired gear
+	if gear[0].ID != "b1" || gear[1].ID != "g1" || gear[2].ID != "g9" {
+		t.Errorf("Unexpected order: %s, %s, %s", gear[0].ID, gear[1].ID, gear[2].ID)
+	}
+	if gear[0].Kind != gearKindBike || gear[0].ActivityCount != 0 || gear[0].LastUsed != nil {
+		t.Errorf("Expected an unused bike, got %+v", gear[0])
+	}
+
+	shoes := gear[1]
+	if shoes.Kind != gearKindShoes || shoes.Brand != "Acme" || !shoes.Primary {
+		t.Errorf("Expected the gear details, got %+v", shoes)
+	}
+	if shoes.ActivityCount != 2 || shoes.ActivityDistance != 10000 || shoes.LastUsed == nil {
+		t.Errorf("Expected the usage of two activities, got %+v", shoes)
+	}
+	if !gear[2].Retired || gear[2].Kind != gearKindShoes {
+		t.Errorf("Expected retired shoes, got %+v", gear[2])
+	}
+
+	// Cached details are not fetched again; only the deleted gear is retried
+	requested = nil
+	if gear := scraper.buildGear(athlete, activities, 10); len(gear) != 3 {
+		t.Errorf("Expected three gear items from the cache, got %+v", gear)
+	}
+	if fmt.Sprint(requested) != "[/gear/g404]" {
+		t.Errorf("Expected only the uncached gear to be fetched, got %v", requested)
+	}
+}
+
+func TestStravaScraper_BuildGearBudget(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		body := `{"id": "g2", "name": "Old racer", "retired": true, "distance": 900000}`
+		if _, err := fmt.Fprint(w, body); err != nil {
+			t.Errorf("Failed to write response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	retired := testStravaActivity(1, "Race")
+	retired.GearID = "g2"
+
+	// Without budget, listed gear is published from its summary and gear
+	// only known from activities waits for a later run
+	scraper := newTestStravaScraper(&stravaTestServer{Server: server}, newMockCache())
+	athlete := &stravaAthlete{ID: 1, Shoes: []stravaGear{{ID: "g1", Name: "Daily trainer", Distance: 400000}}}
+	gear := scraper.buildGear(athlete, []stravaActivity{retired}, 0)
+	if len(gear) != 1 || gear[0].ID != "g1" || gear[0].Distance != 400000 {
+		t.Errorf("Expected only the listed shoes, got %+v", gear)
+	}
+	if len(requested) != 0 {
+		t.Errorf("Expected no gear details to be fetched, got %v", requested)
+	}
+}
+
+func TestStravaScraper_BuildGearFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Authorization Error"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	activity := testStravaActivity(1, "Easy")
+	activity.GearID = "g1"
+
+	scraper := newTestStravaScraper(&stravaTestServer{Server: server}, newMockCache())
+	if gear := scraper.buildGear(nil, []stravaActivity{activity}, 10); gear == nil || len(gear) != 0 {
+		t.Errorf("Expected an empty gear list when no gear details can be fetched, got %+v", gear)
+	}
+}
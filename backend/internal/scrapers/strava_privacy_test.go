@@ -0,0 +1,127 @@
+package scrapers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestStravaScraper_ApplyPrivacy(t *testing.T) {
+	public := testStravaActivity(1, "Morning run")
+	public.Visibility = "everyone"
+	private := testStravaActivity(2, "Private")
+	private.Private = true
+	followers := testStravaActivity(3, "Followers")
+	followers.Visibility = "followers_only"
+	commute := testStravaActivity(4, "Commute")
+	commute.Commute = true
+	hidden := testStravaActivity(5, "Hidden")
+	hidden.HideFromHome = true
+	activities := []stravaActivity{public, private, followers, commute, hidden}
+
+	scraper := NewStravaScraper("id", "secret", "refresh", newMockCache())
+	if published := scraper.applyPrivacy(activities); len(published) != 1 || published[0].ID != 1 {
+		t.Errorf("Expected only the public activity by default, got %+v", published)
+	}
+
+	if err := scraper.SetPrivacyRules(PrivacyRules{
+		Visibilities:    []string{"everyone", "followers_only"},
+		IncludeCommutes: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if published := scraper.applyPrivacy(activities); len(published) != 3 {
+		t.Errorf("Expected public, followers and commute activities, got %d", len(published))
+	}
+}
+
+func TestStravaScraper_ApplyPrivacyRedacts(t *testing.T) {
+	activity := testStravaActivity(1, "Run around my Home street")
+	activity.StartDate = "2024-05-01T07:43:12Z"
+	activity.AverageHeartrate = 150
+	activity.MaxHeartrate = 180
+
+	scraper := NewStravaScraper("id", "secret", "refresh", newMockCache())
+	if err := scraper.SetPrivacyRules(PrivacyRules{
+		RedactPatterns: []string{"(?i)home"},
+		RoundStartTime: time.Hour,
+		DropHeartrate:  true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	published := scraper.applyPrivacy([]stravaActivity{activity})
+	if len(published) != 1 {
+		t.Fatalf("Expected the activity to be published, got %d", len(published))
+	}
+	result := published[0]
+	if result.Name != "Run" {
+		t.Errorf("Expected the name to be replaced by the type, got %q", result.Name)
+	}
+	if result.StartDate != "2024-05-01T07:00:00Z" {
+		t.Errorf("Expected the start time rounded to the hour, got %s", result.StartDate)
+	}
+	if result.AverageHeartrate != 0 || result.MaxHeartrate != 0 {
+		t.Error("Expected heart rate to be dropped")
+	}
+
+	if err := scraper.SetPrivacyRules(PrivacyRules{RedactPatterns: []string{"("}}); err == nil {
+		t.Error("Expected an invalid pattern to fail")
+	}
+}
+
+func TestStravaScraper_ApplyPrivacyOutdatedArchive(t *testing.T) {
+	// Archived before the privacy flags were recorded
+	archived := testStravaActivity(1, "Private run")
+	cache := newMockCache()
+	outdated, err := json.Marshal(map[string]any{"activities": []stravaActivity{archived}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Set(cacheKeyStravaArchive, outdated, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	current := archived
+	current.Private = true
+	current.Visibility = "only_me"
+	server := newStravaTestServer(t, []stravaActivity{current})
+	defer server.Close()
+
+	scraper := newTestStravaScraper(server, cache)
+	activities, err := scraper.syncActivities()
+	if err != nil {
+		t.Fatalf("syncActivities failed: %v", err)
+	}
+	if published := scraper.applyPrivacy(activities); len(published) != 0 {
+		t.Errorf("Expected the refetched private activity to be dropped, got %+v", published)
+	}
+}
+
+func TestStravaScraper_ApplyPrivacyChangedFlags(t *testing.T) {
+	// Archived as public, set to private on Strava later
+	changed := testStravaActivity(1, "Now private")
+	changed.StartDate = time.Now().Add(-20 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	newest := testStravaActivity(2, "Newest")
+	newest.StartDate = time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+
+	private := changed
+	private.Visibility = "only_me"
+	server := newStravaTestServer(t, []stravaActivity{newest, private})
+	defer server.Close()
+
+	cache := newMockCache()
+	scraper := newTestStravaScraper(server, cache)
+	if err := scraper.saveArchive(&stravaArchive{Activities: []stravaActivity{newest, changed}, FullSyncAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The recent window is refetched although it predates the overlap
+	activities, err := scraper.syncActivities()
+	if err != nil {
+		t.Fatalf("syncActivities failed: %v", err)
+	}
+	if published := scraper.applyPrivacy(activities); len(published) != 1 || published[0].ID != 2 {
+		t.Errorf("Expected the activity made private to be dropped, got %+v", published)
+	}
+}
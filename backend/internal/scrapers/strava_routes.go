@@ -0,0 +1,402 @@
+package scrapers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mrcodeeu/homepage/internal/models"
+)
+
+const (
+	// defaultRoutePoints is the point budget of a simplified route
+	defaultRoutePoints = 100
+	// defaultRouteLimit is how many of the most recent activities get a route
+	defaultRouteLimit = 50
+	// defaultHeatmapCell is the edge length of a heatmap cell in meters
+	defaultHeatmapCell = 250
+	// defaultPrivacyRadius applies to privacy zones configured without radius
+	defaultPrivacyRadius = 500
+
+	earthRadius     = 6371000.0 // meters
+	metersPerDegree = earthRadius * math.Pi / 180
+)
+
+// latLng is a coordinate in degrees
+type latLng struct {
+	lat, lng float64
+}
+
+// PrivacyZone is a circle, e.g. around a home, in which route points are
+// dropped
+type PrivacyZone struct {
+	Lat    float64
+	Lng    float64
+	Radius float64 // meters
+}
+
+// ParsePrivacyZones parses zones separated by ";", each "lat,lng" or
+// "lat,lng,radius" with the radius in meters
+func ParsePrivacyZones(spec string) ([]PrivacyZone, error) {
+	zones := make([]PrivacyZone, 0)
+	for _, entry := range strings.Split(spec, ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ",")
+		if len(parts) != 2 && len(parts) != 3 {
+			return nil, fmt.Errorf("invalid privacy zone %q (expected lat,lng[,radius])", entry)
+		}
+		values := make([]float64, len(parts))
+		for i, part := range parts {
+			value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid privacy zone %q: %w", entry, err)
+			}
+			values[i] = value
+		}
+		zone := PrivacyZone{Lat: values[0], Lng: values[1], Radius: defaultPrivacyRadius}
+		if len(values) == 3 {
+			zone.Radius = values[2]
+		}
+		if math.Abs(zone.Lat) > 90 || math.Abs(zone.Lng) > 180 || zone.Radius <= 0 {
+			return nil, fmt.Errorf("invalid privacy zone %q", entry)
+		}
+		zones = append(zones, zone)
+	}
+	return zones, nil
+}
+
+// SetPrivacyZones sets the zones in which route points, start coordinates
+// and heatmap cells are hidden
+func (s *StravaScraper) SetPrivacyZones(zones []PrivacyZone) {
+	s.privacyZones = zones
+}
+
+// SetRouteOptions sets the point budget of each route, how many of the most
+// recent activities get a route and the heatmap cell size in meters.
+// Non-positive values keep the current setting.
+func (s *StravaScraper) SetRouteOptions(points, limit, cellSize int) {
+	if points > 1 {
+		s.routePoints = points
+	}
+	if limit > 0 {
+		s.routeLimit = limit
+	}
+	if cellSize > 0 {
+		s.heatmapCell = cellSize
+	}
+}
+
+// decodePolyline decodes a Google encoded polyline with 5 decimal places,
+// the format of Strava's summary_polyline
+func decodePolyline(encoded string) ([]latLng, error) {
+	points := make([]latLng, 0, len(encoded)/4)
+	var lat, lng int
+	for i := 0; i < len(encoded); {
+		for _, coordinate := range []*int{&lat, &lng} {
+			result, shift := 0, 0
+			for {
+				if i >= len(encoded) {
+					return nil, errors.New("truncated polyline")
+				}
+				b := int(encoded[i]) - 63
+				i++
+				if b < 0 || b > 63 {
+					return nil, fmt.Errorf("invalid polyline character %q", encoded[i-1])
+				}
+				result |= (b & 0x1f) << shift
+				shift += 5
+				if b < 0x20 {
+					break
+				}
+			}
+			if result&1 != 0 {
+				*coordinate += ^(result >> 1)
+			} else {
+				*coordinate += result >> 1
+			}
+		}
+		points = append(points, latLng{lat: float64(lat) / 1e5, lng: float64(lng) / 1e5})
+	}
+	return points, nil
+}
+
+// distanceMeters returns the great-circle distance between two points
+func distanceMeters(a, b latLng) float64 {
+	lat1, lat2 := a.lat*math.Pi/180, b.lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.lng - a.lng) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+// inPrivacyZone reports whether the point lies within any zone
+func inPrivacyZone(point latLng, zones []PrivacyZone) bool {
+	for _, zone := range zones {
+		if distanceMeters(point, latLng{lat: zone.Lat, lng: zone.Lng}) <= zone.Radius {
+			return true
+		}
+	}
+	return false
+}
+
+// crossesPrivacyZone reports whether the straight line from a to b passes
+// through any zone, even if both ends lie outside of it
+func crossesPrivacyZone(a, b latLng, zones []PrivacyZone) bool {
+	for _, zone := range zones {
+		// Project onto a plane in meters centered on the zone, which is
+		// accurate at the scale of a zone
+		scale := earthRadius * math.Pi / 180
+		lngScale := scale * math.Cos(zone.Lat*math.Pi/180)
+		ax, ay := (a.lng-zone.Lng)*lngScale, (a.lat-zone.Lat)*scale
+		bx, by := (b.lng-zone.Lng)*lngScale, (b.lat-zone.Lat)*scale
+
+		// Closest point of the line to the zone center
+		dx, dy := bx-ax, by-ay
+		t := 0.0
+		if length := dx*dx + dy*dy; length > 0 {
+			t = min(max(-(ax*dx+ay*dy)/length, 0), 1)
+		}
+		if math.Hypot(ax+t*dx, ay+t*dy) <= zone.Radius {
+			return true
+		}
+	}
+	return false
+}
+
+// trimPrivacyZones drops the points inside privacy zones. A route passing
+// through a zone is split, both where points lie inside it and where the
+// line between two points outside crosses it, so no line crosses a zone;
+// segments with fewer than two points are dropped.
+func trimPrivacyZones(points []latLng, zones []PrivacyZone) [][]latLng {
+	segments := make([][]latLng, 0, 1)
+	current := make([]latLng, 0, len(points))
+	for _, point := range points {
+		inside := inPrivacyZone(point, zones)
+		if inside || (len(current) > 0 && crossesPrivacyZone(current[len(current)-1], point, zones)) {
+			if len(current) > 1 {
+				segments = append(segments, current)
+			}
+			current = make([]latLng, 0, len(points))
+		}
+		if inside {
+			continue
+		}
+		current = append(current, point)
+	}
+	if len(current) > 1 {
+		segments = append(segments, current)
+	}
+	return segments
+}
+
+// perpendicularDistance returns the distance of p from the line through a
+// and b in degrees, with longitude scaled to the latitude
+func perpendicularDistance(p, a, b latLng) float64 {
+	scale := math.Cos(a.lat * math.Pi / 180)
+	px, py := (p.lng-a.lng)*scale, p.lat-a.lat
+	bx, by := (b.lng-a.lng)*scale, b.lat-a.lat
+	length := math.Hypot(bx, by)
+	if length == 0 {
+		return math.Hypot(px, py)
+	}
+	return math.Abs(px*by-py*bx) / length
+}
+
+// simplifyRoute reduces points to at most budget points. Like
+// Douglas-Peucker it keeps the points that deviate most from the simplified
+// line, but stops at the point budget instead of a distance tolerance.
+// Lines that would cross a privacy zone are always split, even beyond the
+// budget, so simplifying never draws a line through a zone the original
+// points avoid.
+func simplifyRoute(points []latLng, budget int, zones []PrivacyZone) []latLng {
+	if len(points) <= budget || budget < 2 {
+		return points
+	}
+
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+	kept := 2
+
+	// Each span between kept points proposes its farthest point
+	type span struct {
+		from, to, farthest int
+		distance           float64
+	}
+	farthest := func(from, to int) span {
+		result := span{from: from, to: to, farthest: -1}
+		for i := from + 1; i < to; i++ {
+			if d := perpendicularDistance(points[i], points[from], points[to]); d > result.distance || result.farthest < 0 {
+				result.farthest, result.distance = i, d
+			}
+		}
+		if result.farthest >= 0 && crossesPrivacyZone(points[from], points[to], zones) {
+			result.distance = math.Inf(1)
+		}
+		return result
+	}
+
+	spans := []span{farthest(0, len(points)-1)}
+	for {
+		best := -1
+		for i, candidate := range spans {
+			if candidate.farthest >= 0 && (best < 0 || candidate.distance > spans[best].distance) {
+				best = i
+			}
+		}
+		if best < 0 || (kept >= budget && !math.IsInf(spans[best].distance, 1)) {
+			break
+		}
+		split := spans[best]
+		keep[split.farthest] = true
+		kept++
+		spans[best] = farthest(split.from, split.farthest)
+		spans = append(spans, farthest(split.farthest, split.to))
+	}
+
+	result := make([]latLng, 0, kept)
+	for i, point := range points {
+		if keep[i] {
+			result = append(result, point)
+		}
+	}
+	return result
+}
+
+// geoJSONCoordinates converts points into GeoJSON [lng, lat] pairs rounded
+// to 5 decimal places (about 1 m)
+func geoJSONCoordinates(points []latLng) [][]float64 {
+	coordinates := make([][]float64, len(points))
+	for i, point := range points {
+		coordinates[i] = []float64{math.Round(point.lng*1e5) / 1e5, math.Round(point.lat*1e5) / 1e5}
+	}
+	return coordinates
+}
+
+// buildMaps creates GeoJSON routes of the most recent activities and a
+// heatmap of all activities. Points inside privacy zones are dropped
+// before anything else happens.
+func (s *StravaScraper) buildMaps(activities []stravaActivity) models.StravaMaps {
+	sorted := make([]stravaActivity, 0, len(activities))
+	for _, activity := range activities {
+		if activity.Map.SummaryPolyline != "" {
+			sorted = append(sorted, activity)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].StartDate > sorted[j].StartDate })
+
+	routes := models.GeoJSONFeatureCollection{Type: "FeatureCollection", Features: make([]models.GeoJSONFeature, 0)}
+	heatmap := newHeatmapGrid(float64(s.heatmapCell), s.privacyZones)
+
+	for _, activity := range sorted {
+		points, err := decodePolyline(activity.Map.SummaryPolyline)
+		if err != nil {
+			continue
+		}
+		segments := trimPrivacyZones(points, s.privacyZones)
+		if len(segments) == 0 {
+			continue
+		}
+		heatmap.add(segments)
+
+		if len(routes.Features) >= s.routeLimit {
+			continue
+		}
+		routes.Features = append(routes.Features, s.routeFeature(activity, segments))
+	}
+
+	return models.StravaMaps{Routes: routes, Heatmap: heatmap.result()}
+}
+
+// routeFeature builds the GeoJSON feature of an activity, sharing the point
+// budget between its segments
+func (s *StravaScraper) routeFeature(activity stravaActivity, segments [][]latLng) models.GeoJSONFeature {
+	total := 0
+	for _, segment := range segments {
+		total += len(segment)
+	}
+
+	lines := make([][][]float64, 0, len(segments))
+	for _, segment := range segments {
+		budget := max(s.routePoints*len(segment)/total, 2)
+		lines = append(lines, geoJSONCoordinates(simplifyRoute(segment, budget, s.privacyZones)))
+	}
+
+	geometry := models.GeoJSONGeometry{Type: "MultiLineString", Coordinates: lines}
+	if len(lines) == 1 {
+		geometry = models.GeoJSONGeometry{Type: "LineString", Coordinates: lines[0]}
+	}
+
+	properties := map[string]any{
+		"id":         activity.ID,
+		"name":       activity.Name,
+		"type":       activity.sportType(),
+		"distance":   activity.Distance,
+		"start_date": activity.StartDate,
+	}
+	if len(activity.StartLatLng) == 2 {
+		start := latLng{lat: activity.StartLatLng[0], lng: activity.StartLatLng[1]}
+		if !inPrivacyZone(start, s.privacyZones) {
+			properties["start"] = geoJSONCoordinates([]latLng{start})[0]
+		}
+	}
+
+	return models.GeoJSONFeature{Type: "Feature", Geometry: geometry, Properties: properties}
+}
+
+// heatmapGrid counts the activities passing through each grid cell. Cells
+// span the same number of degrees in latitude and longitude. Cells whose
+// published center lies within a privacy zone are left out, even if the
+// points counted in them are outside.
+type heatmapGrid struct {
+	cellDegrees float64
+	counts      map[[2]int]int
+	zones       []PrivacyZone
+}
+
+func newHeatmapGrid(cellMeters float64, zones []PrivacyZone) *heatmapGrid {
+	return &heatmapGrid{cellDegrees: cellMeters / metersPerDegree, counts: make(map[[2]int]int), zones: zones}
+}
+
+// add counts each cell the activity passes through once
+func (g *heatmapGrid) add(segments [][]latLng) {
+	seen := make(map[[2]int]bool)
+	for _, segment := range segments {
+		for _, point := range segment {
+			cell := [2]int{int(math.Floor(point.lat / g.cellDegrees)), int(math.Floor(point.lng / g.cellDegrees))}
+			if !seen[cell] {
+				seen[cell] = true
+				g.counts[cell]++
+			}
+		}
+	}
+}
+
+// result lists the cells by count, then by position
+func (g *heatmapGrid) result() models.StravaHeatmap {
+	cells := make([]models.StravaHeatmapCell, 0, len(g.counts))
+	for cell, count := range g.counts {
+		center := latLng{
+			lat: math.Round((float64(cell[0])+0.5)*g.cellDegrees*1e5) / 1e5,
+			lng: math.Round((float64(cell[1])+0.5)*g.cellDegrees*1e5) / 1e5,
+		}
+		if inPrivacyZone(center, g.zones) {
+			continue
+		}
+		cells = append(cells, models.StravaHeatmapCell{Lat: center.lat, Lng: center.lng, Count: count})
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Count != cells[j].Count {
+			return cells[i].Count > cells[j].Count
+		}
+		if cells[i].Lat != cells[j].Lat {
+			return cells[i].Lat < cells[j].Lat
+		}
+		return cells[i].Lng < cells[j].Lng
+	})
+	return models.StravaHeatmap{CellSize: math.Round(g.cellDegrees*1e7) / 1e7, Cells: cells}
+}
@@ -0,0 +1,207 @@
+package scrapers
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// encodePolyline is the inverse of decodePolyline, used to build fixtures
+func encodePolyline(points []latLng) string {
+	var b strings.Builder
+	var prevLat, prevLng int
+	for _, point := range points {
+		lat, lng := int(math.Round(point.lat*1e5)), int(math.Round(point.lng*1e5))
+		for _, delta := range []int{lat - prevLat, lng - prevLng} {
+			value := delta << 1
+			if delta < 0 {
+				value = ^value
+			}
+			for value >= 0x20 {
+				b.WriteByte(byte((0x20 | (value & 0x1f)) + 63))
+				value >>= 5
+			}
+			b.WriteByte(byte(value + 63))
+		}
+		prevLat, prevLng = lat, lng
+	}
+	return b.String()
+}
+
+func TestDecodePolyline(t *testing.T) {
+	// Example from the Google polyline algorithm documentation
+	points, err := decodePolyline("_p~iF~ps|U_ulLnnqC_mqNvxq`@")
+	if err != nil {
+		t.Fatalf("decodePolyline failed: %v", err)
+	}
+	expected := []latLng{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	if len(points) != len(expected) {
+		t.Fatalf("Expected %d points, got %d", len(expected), len(points))
+	}
+	for i := range expected {
+		if math.Abs(points[i].lat-expected[i].lat) > 1e-9 || math.Abs(points[i].lng-expected[i].lng) > 1e-9 {
+			t.Errorf("Point %d: expected %v, got %v", i, expected[i], points[i])
+		}
+	}
+
+	if _, err := decodePolyline("_p~iF~ps|"); err == nil {
+		t.Error("Expected a truncated polyline to fail")
+	}
+}
+
+func TestSimplifyRoute(t *testing.T) {
+	// A straight line with one sharp detour in the middle
+	points := make([]latLng, 0, 101)
+	for i := 0; i <= 100; i++ {
+		points = append(points, latLng{lat: 48, lng: 16 + float64(i)*0.001})
+	}
+	points[50].lat = 48.01
+
+	simplified := simplifyRoute(points, 5, nil)
+	if len(simplified) != 5 {
+		t.Fatalf("Expected 5 points, got %d", len(simplified))
+	}
+	if simplified[0] != points[0] || simplified[4] != points[100] {
+		t.Error("Expected the endpoints to be kept")
+	}
+	found := false
+	for _, point := range simplified {
+		found = found || point == points[50]
+	}
+	if !found {
+		t.Error("Expected the detour to be kept")
+	}
+
+	if short := simplifyRoute(points[:3], 5, nil); len(short) != 3 {
+		t.Errorf("Expected routes within the budget to be unchanged, got %d points", len(short))
+	}
+}
+
+func TestTrimPrivacyZones(t *testing.T) {
+	zone := PrivacyZone{Lat: 48.2, Lng: 16.37, Radius: 200}
+	zones := []PrivacyZone{zone}
+
+	// Two vertices about 1 km north and south of the zone: neither lies
+	// inside, but the line between them passes straight through it
+	north := latLng{lat: 48.209, lng: 16.37}
+	south := latLng{lat: 48.191, lng: 16.37}
+	further := latLng{lat: 48.185, lng: 16.37}
+	if !crossesPrivacyZone(north, south, zones) {
+		t.Fatal("Expected the line through the zone to cross it")
+	}
+	if crossesPrivacyZone(south, further, zones) {
+		t.Fatal("Expected the line south of the zone not to cross it")
+	}
+
+	segments := trimPrivacyZones([]latLng{north, south, further}, zones)
+	if len(segments) != 1 || len(segments[0]) != 2 || segments[0][0] != south {
+		t.Errorf("Expected only the segment south of the zone, got %v", segments)
+	}
+
+	// A route around the zone must not be simplified into a line across it
+	around := []latLng{north, {lat: 48.209, lng: 16.38}, {lat: 48.2, lng: 16.385}, {lat: 48.191, lng: 16.38}, south}
+	if segments := trimPrivacyZones(around, zones); len(segments) != 1 || len(segments[0]) != len(around) {
+		t.Fatalf("Expected the route around the zone to stay whole, got %v", segments)
+	}
+	simplified := simplifyRoute(around, 2, zones)
+	for i := 1; i < len(simplified); i++ {
+		if crossesPrivacyZone(simplified[i-1], simplified[i], zones) {
+			t.Errorf("Expected no simplified line to cross the zone, got %v", simplified)
+		}
+	}
+}
+
+func TestParsePrivacyZones(t *testing.T) {
+	zones, err := ParsePrivacyZones("48.2082,16.3738; 47.0707,15.4395,800")
+	if err != nil {
+		t.Fatalf("ParsePrivacyZones failed: %v", err)
+	}
+	if len(zones) != 2 || zones[0].Radius != defaultPrivacyRadius || zones[1].Radius != 800 {
+		t.Errorf("Unexpected zones: %+v", zones)
+	}
+
+	for _, spec := range []string{"48.2", "48.2,abc", "95,16", "48.2,16.3,-5"} {
+		if _, err := ParsePrivacyZones(spec); err == nil {
+			t.Errorf("Expected %q to be rejected", spec)
+		}
+	}
+}
+
+func TestHeatmapGridPrivacyZones(t *testing.T) {
+	// 1 km cells; the zone covers the center of the first cell, but the
+	// point counted in it lies outside the zone
+	cell := 1000 / metersPerDegree
+	grid := newHeatmapGrid(1000, []PrivacyZone{{Lat: 0.5 * cell, Lng: 0.5 * cell, Radius: 200}})
+
+	edge := latLng{lat: 0.1 * cell, lng: 0.1 * cell}
+	away := latLng{lat: 2.5 * cell, lng: 0.5 * cell}
+	if inPrivacyZone(edge, grid.zones) {
+		t.Fatal("Expected the counted point to lie outside the zone")
+	}
+	grid.add([][]latLng{{edge, away}})
+
+	cells := grid.result().Cells
+	if len(cells) != 1 {
+		t.Fatalf("Expected only the cell away from the zone, got %+v", cells)
+	}
+	if inPrivacyZone(latLng{lat: cells[0].Lat, lng: cells[0].Lng}, grid.zones) {
+		t.Errorf("Expected no cell center within the zone, got %+v", cells[0])
+	}
+}
+
+func TestStravaScraper_BuildMaps(t *testing.T) {
+	// Out and back from home; about 111 m per point
+	home := latLng{lat: 48.2, lng: 16.37}
+	points := make([]latLng, 0, 41)
+	for i := 0; i <= 20; i++ {
+		points = append(points, latLng{lat: home.lat + float64(i)*0.001, lng: home.lng})
+	}
+	for i := 19; i >= 0; i-- {
+		points = append(points, latLng{lat: home.lat + float64(i)*0.001, lng: home.lng})
+	}
+
+	run := testStravaActivity(2, "Home run")
+	run.Map.SummaryPolyline = encodePolyline(points)
+	run.StartLatLng = []float64{home.lat, home.lng}
+	older := testStravaActivity(1, "Older run")
+	older.Map.SummaryPolyline = encodePolyline(points[5:15])
+	indoor := testStravaActivity(3, "Treadmill")
+
+	scraper := NewStravaScraper("id", "secret", "refresh", newMockCache())
+	scraper.SetPrivacyZones([]PrivacyZone{{Lat: home.lat, Lng: home.lng, Radius: 500}})
+	scraper.SetRouteOptions(10, 1, 250)
+
+	maps := scraper.buildMaps([]stravaActivity{older, run, indoor})
+
+	// Only the most recent activity with a polyline gets a route
+	if len(maps.Routes.Features) != 1 {
+		t.Fatalf("Expected one route, got %d", len(maps.Routes.Features))
+	}
+	feature := maps.Routes.Features[0]
+	if feature.Properties["id"] != int64(2) || feature.Geometry.Type != "LineString" {
+		t.Errorf("Unexpected route feature: %+v", feature)
+	}
+	if _, ok := feature.Properties["start"]; ok {
+		t.Error("Expected the start inside the privacy zone to be hidden")
+	}
+
+	coordinates := feature.Geometry.Coordinates.([][]float64)
+	if len(coordinates) > 10 {
+		t.Errorf("Expected at most 10 points, got %d", len(coordinates))
+	}
+	for _, coordinate := range coordinates {
+		if inPrivacyZone(latLng{lat: coordinate[1], lng: coordinate[0]}, scraper.privacyZones) {
+			t.Errorf("Expected no point within the privacy zone, got %v", coordinate)
+		}
+	}
+
+	// Both activities count towards the heatmap, each cell once per activity
+	if len(maps.Heatmap.Cells) == 0 || maps.Heatmap.Cells[0].Count != 2 {
+		t.Errorf("Expected cells shared by both activities first, got %+v", maps.Heatmap.Cells)
+	}
+	for _, cell := range maps.Heatmap.Cells {
+		if cell.Count > 2 {
+			t.Errorf("Expected each activity to count once per cell, got %+v", cell)
+		}
+	}
+}
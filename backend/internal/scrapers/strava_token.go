@@ -0,0 +1,69 @@
+package scrapers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mrcodeeu/homepage/internal/storage"
+)
+
+// Where a refresh token came from, for logging
+const (
+	refreshTokenSourceStore  = "token store"
+	refreshTokenSourceConfig = "STRAVA_REFRESH_TOKEN"
+)
+
+// refreshTokenCandidate is a refresh token to try and where it came from
+type refreshTokenCandidate struct {
+	token  string
+	source string
+}
+
+// SetTokenStore sets where refresh tokens are persisted. A token found in
+// the store is preferred over the one passed to NewStravaScraper, which is
+// only used when the store is empty or its token is rejected.
+func (s *StravaScraper) SetTokenStore(store storage.TokenStore) {
+	s.tokenStore = store
+}
+
+// refreshTokenCandidates returns the refresh tokens to try, stored first
+func (s *StravaScraper) refreshTokenCandidates() []refreshTokenCandidate {
+	candidates := make([]refreshTokenCandidate, 0, 2)
+	if s.tokenStore != nil {
+		stored, updatedAt, err := s.tokenStore.Load()
+		switch {
+		case err != nil:
+			log.Printf("Warning: failed to load stored Strava refresh token: %v", err)
+		case stored != "":
+			log.Printf("Using Strava refresh token from the token store (saved %s)", updatedAt.Format(time.RFC3339))
+			candidates = append(candidates, refreshTokenCandidate{token: stored, source: refreshTokenSourceStore})
+		}
+	}
+	if s.refreshToken != "" && (len(candidates) == 0 || candidates[0].token != s.refreshToken) {
+		candidates = append(candidates, refreshTokenCandidate{token: s.refreshToken, source: refreshTokenSourceConfig})
+	}
+	return candidates
+}
+
+// persistRefreshToken saves the current refresh token to the token store.
+// Without a store the token only lives until the process exits.
+func (s *StravaScraper) persistRefreshToken() {
+	if s.tokenStore == nil {
+		log.Println("Warning: no Strava token store configured, the rotated refresh token is lost when the process exits")
+		return
+	}
+	if err := s.tokenStore.Save(s.refreshToken); err != nil {
+		log.Printf("Warning: failed to save Strava refresh token: %v", err)
+		return
+	}
+	log.Printf("✓ Saved Strava refresh token to the token store%s", storePath(s.tokenStore))
+}
+
+// storePath describes where a file-backed store keeps its token
+func storePath(store storage.TokenStore) string {
+	if file, ok := store.(*storage.FileTokenStore); ok {
+		return fmt.Sprintf(" (%s)", file.Path())
+	}
+	return ""
+}
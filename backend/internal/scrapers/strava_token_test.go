@@ -0,0 +1,105 @@
+package scrapers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/mrcodeeu/homepage/internal/storage"
+)
+
+// newStravaTokenServer accepts the refresh tokens in valid and answers each
+// exchange with the given rotated refresh token ("" keeps the current one).
+// The refresh tokens sent are recorded in order.
+func newStravaTokenServer(t *testing.T, valid map[string]bool, rotated string, sent *[]string) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Invalid token request: %v", err)
+		}
+		refreshToken := r.PostForm.Get("refresh_token")
+		mu.Lock()
+		*sent = append(*sent, refreshToken)
+		mu.Unlock()
+
+		if !valid[refreshToken] {
+			http.Error(w, `{"message": "Bad Request"}`, http.StatusBadRequest)
+			return
+		}
+		if _, err := fmt.Fprintf(w, `{"access_token": "access", "refresh_token": %q, "expires_at": 4102444800}`,
+			rotated); err != nil {
+			t.Errorf("Failed to write response: %v", err)
+		}
+	}))
+}
+
+func newTokenTestScraper(server *httptest.Server, refreshToken string, store storage.TokenStore) *StravaScraper {
+	scraper := NewStravaScraper("id", "secret", refreshToken, newMockCache())
+	scraper.client = server.Client()
+	scraper.tokenURL = server.URL
+	if store != nil {
+		scraper.SetTokenStore(store)
+	}
+	return scraper
+}
+
+func TestStravaScraper_PersistsRotatedToken(t *testing.T) {
+	var sent []string
+	server := newStravaTokenServer(t, map[string]bool{"env-token": true, "rotated-token": true}, "rotated-token", &sent)
+	defer server.Close()
+	store := storage.NewFileTokenStore(filepath.Join(t.TempDir(), "strava_token.json"), "passphrase")
+
+	// The first run only knows the configured token and stores the rotated one
+	if err := newTokenTestScraper(server, "env-token", store).ensureAccessToken(); err != nil {
+		t.Fatalf("ensureAccessToken failed: %v", err)
+	}
+	if stored, _, err := store.Load(); err != nil || stored != "rotated-token" {
+		t.Fatalf("Expected the rotated token to be stored, got %q %v", stored, err)
+	}
+
+	// The next run prefers the stored token over the configured one
+	sent = nil
+	if err := newTokenTestScraper(server, "env-token", store).ensureAccessToken(); err != nil {
+		t.Fatalf("ensureAccessToken failed: %v", err)
+	}
+	if len(sent) != 1 || sent[0] != "rotated-token" {
+		t.Errorf("Expected only the stored token to be used, got %v", sent)
+	}
+}
+
+func TestStravaScraper_FallsBackToConfiguredToken(t *testing.T) {
+	var sent []string
+	server := newStravaTokenServer(t, map[string]bool{"new-env-token": true}, "", &sent)
+	defer server.Close()
+	store := storage.NewFileTokenStore(filepath.Join(t.TempDir(), "strava_token.json"), "")
+	if err := store.Save("revoked-token"); err != nil {
+		t.Fatal(err)
+	}
+
+	scraper := newTokenTestScraper(server, "new-env-token", store)
+	if err := scraper.ensureAccessToken(); err != nil {
+		t.Fatalf("ensureAccessToken failed: %v", err)
+	}
+	if len(sent) != 2 || sent[0] != "revoked-token" || sent[1] != "new-env-token" {
+		t.Errorf("Expected the stored token to be tried before the configured one, got %v", sent)
+	}
+	if stored, _, _ := store.Load(); stored != "new-env-token" {
+		t.Errorf("Expected the working configured token to replace the stored one, got %q", stored)
+	}
+	if scraper.accessToken != "access" {
+		t.Errorf("Expected an access token, got %q", scraper.accessToken)
+	}
+}
+
+func TestStravaScraper_TokenExchangeFails(t *testing.T) {
+	var sent []string
+	server := newStravaTokenServer(t, map[string]bool{}, "", &sent)
+	defer server.Close()
+
+	if err := newTokenTestScraper(server, "bad-token", nil).ensureAccessToken(); err == nil {
+		t.Error("Expected a rejected token to fail")
+	}
+}
@@ -0,0 +1,128 @@
+package scrapers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mrcodeeu/homepage/internal/models"
+)
+
+// defaultTrendMonths is how far back weekly and monthly trends reach
+const defaultTrendMonths = 12
+
+// periodDateFormat formats the first day of a trend period
+const periodDateFormat = "2006-01-02"
+
+// SetTrendMonths sets how many months (including the current one) weekly
+// and monthly trends cover
+func (s *StravaScraper) SetTrendMonths(months int) {
+	if months > 0 {
+		s.trendMonths = months
+	}
+}
+
+// SetTimezone sets the IANA time zone, e.g. "Europe/Vienna", in which
+// weeks and months start
+func (s *StravaScraper) SetTimezone(name string) error {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("invalid time zone %q: %w", name, err)
+	}
+	s.location = loc
+	return nil
+}
+
+// startOfWeek returns Monday 00:00 of the week containing t, in t's location
+func startOfWeek(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+	return day.AddDate(0, 0, -offset)
+}
+
+// startOfMonth returns the first day of t's month at 00:00, in t's location
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// buildTrends aggregates activities into weekly and monthly periods for the
+// last months (including the current one) up to now, broken down by the
+// discipline each activity type maps to. Periods start at
+// midnight in loc, weeks on Monday. Every period is listed, including those
+// without activities, oldest first.
+func buildTrends(activities []stravaActivity, disciplineOf func(string) string, months int, loc *time.Location, now time.Time) models.StravaTrends {
+	now = now.In(loc)
+	firstMonth := startOfMonth(now).AddDate(0, -(months - 1), 0)
+	firstWeek := startOfWeek(firstMonth)
+
+	monthly := newPeriods(firstMonth, now, func(t time.Time) time.Time { return t.AddDate(0, 1, 0) })
+	weekly := newPeriods(firstWeek, now, func(t time.Time) time.Time { return t.AddDate(0, 0, 7) })
+
+	for _, raw := range activities {
+		start, err := time.Parse(time.RFC3339, raw.StartDate)
+		if err != nil {
+			continue
+		}
+		start = start.In(loc)
+		if start.Before(firstWeek) || start.After(now) {
+			continue
+		}
+
+		activity := convertActivity(raw)
+		discipline := disciplineOf(raw.sportType())
+		if !start.Before(firstMonth) {
+			monthly.add(startOfMonth(start), discipline, activity)
+		}
+		weekly.add(startOfWeek(start), discipline, activity)
+	}
+
+	return models.StravaTrends{
+		Timezone: loc.String(),
+		Weekly:   weekly.list,
+		Monthly:  monthly.list,
+	}
+}
+
+// trendPeriods holds consecutive periods indexed by their start date
+type trendPeriods struct {
+	list  []models.StravaPeriod
+	index map[string]int
+}
+
+// newPeriods creates the periods from first up to the one containing now
+func newPeriods(first, now time.Time, next func(time.Time) time.Time) *trendPeriods {
+	periods := &trendPeriods{index: make(map[string]int)}
+	for start := first; !start.After(now); start = next(start) {
+		key := start.Format(periodDateFormat)
+		periods.index[key] = len(periods.list)
+		periods.list = append(periods.list, models.StravaPeriod{
+			Start:       key,
+			Disciplines: make(map[string]models.StravaStats),
+		})
+	}
+	return periods
+}
+
+// add counts the activity towards the period starting at start
+func (p *trendPeriods) add(start time.Time, discipline string, activity models.StravaActivity) {
+	i, ok := p.index[start.Format(periodDateFormat)]
+	if !ok {
+		return
+	}
+	period := &p.list[i]
+	addToStats(&period.Total, activity)
+	if discipline == "" {
+		return
+	}
+	stats := period.Disciplines[discipline]
+	addToStats(&stats, activity)
+	period.Disciplines[discipline] = stats
+}
+
+// addToStats adds an activity to aggregate statistics
+func addToStats(stats *models.StravaStats, activity models.StravaActivity) {
+	stats.Count++
+	stats.Distance += activity.Distance
+	stats.MovingTime += activity.MovingTime
+	stats.ElapsedTime += activity.ElapsedTime
+	stats.ElevationGain += activity.TotalElevationGain
+}
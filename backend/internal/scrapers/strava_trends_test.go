@@ -0,0 +1,63 @@
+package scrapers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildTrends(t *testing.T) {
+	vienna, err := time.LoadLocation("Europe/Vienna")
+	if err != nil {
+		t.Skipf("Time zone data unavailable: %v", err)
+	}
+	now := time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC)
+
+	activities := []stravaActivity{
+		// Sunday 23:30 UTC is already Monday in Vienna
+		{ID: 1, Type: "Run", Distance: 10000, MovingTime: 3000, StartDate: "2024-03-17T23:30:00Z"},
+		{ID: 2, Type: "Ride", Distance: 40000, MovingTime: 5400, TotalElevationGain: 300, StartDate: "2024-03-18T07:00:00Z"},
+		// 31 January 23:30 UTC is in February in Vienna
+		{ID: 3, Type: "Run", Distance: 5000, MovingTime: 1500, StartDate: "2024-01-31T23:30:00Z"},
+		// Before the first week
+		{ID: 4, Type: "Run", Distance: 5000, MovingTime: 1500, StartDate: "2024-01-28T10:00:00Z"},
+	}
+
+	taxonomy := mustDisciplineTaxonomy(defaultDisciplines)
+	trends := buildTrends(activities, taxonomy.disciplineOf, 2, vienna, now)
+	if trends.Timezone != "Europe/Vienna" {
+		t.Errorf("Expected the time zone to be reported, got %q", trends.Timezone)
+	}
+
+	if len(trends.Monthly) != 2 || trends.Monthly[0].Start != "2024-02-01" || trends.Monthly[1].Start != "2024-03-01" {
+		t.Fatalf("Expected February and March, got %+v", trends.Monthly)
+	}
+	if trends.Monthly[0].Total.Count != 1 || trends.Monthly[0].Total.Distance != 5000 {
+		t.Errorf("Expected the late January run in February, got %+v", trends.Monthly[0].Total)
+	}
+	march := trends.Monthly[1]
+	if march.Total.Count != 2 || march.Disciplines["cycling"].Distance != 40000 || march.Disciplines["running"].Count != 1 {
+		t.Errorf("Unexpected March totals: %+v", march)
+	}
+
+	// Weeks start on the Monday before the first month
+	if len(trends.Weekly) != 8 || trends.Weekly[0].Start != "2024-01-29" || trends.Weekly[7].Start != "2024-03-18" {
+		t.Fatalf("Expected 8 weeks from 2024-01-29, got %d starting %s", len(trends.Weekly), trends.Weekly[0].Start)
+	}
+	lastWeek := trends.Weekly[7]
+	if lastWeek.Total.Count != 2 || lastWeek.Total.ElevationGain != 300 {
+		t.Errorf("Expected both March activities in the last week, got %+v", lastWeek.Total)
+	}
+	if empty := trends.Weekly[3]; empty.Total.Count != 0 || len(empty.Disciplines) != 0 {
+		t.Errorf("Expected weeks without activities to be empty, got %+v", empty)
+	}
+}
+
+func TestStravaScraper_SetTimezone(t *testing.T) {
+	scraper := NewStravaScraper("id", "secret", "refresh", newMockCache())
+	if err := scraper.SetTimezone("Not/AZone"); err == nil {
+		t.Error("Expected an unknown time zone to fail")
+	}
+	if scraper.location != time.UTC {
+		t.Errorf("Expected UTC to be kept, got %v", scraper.location)
+	}
+}
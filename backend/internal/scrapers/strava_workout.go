@@ -0,0 +1,56 @@
+package scrapers
+
+import (
+	"sort"
+
+	"github.com/mrcodeeu/homepage/internal/models"
+)
+
+// Workout types as published; Strava encodes them per sport as numbers
+const (
+	workoutTypeRace    = "race"
+	workoutTypeLongRun = "long_run"
+	workoutTypeWorkout = "workout"
+)
+
+// stravaWorkoutTypes maps Strava's workout_type codes: 0-3 for runs and
+// 10-12 for rides, where 0 and 10 are the default
+var stravaWorkoutTypes = map[int]string{
+	1:  workoutTypeRace,
+	2:  workoutTypeLongRun,
+	3:  workoutTypeWorkout,
+	11: workoutTypeRace,
+	12: workoutTypeWorkout,
+}
+
+// sportType returns the activity's sport type, falling back to the legacy
+// type for activities archived before sport types were recorded
+func (a stravaActivity) sportType() string {
+	if a.SportType != "" {
+		return a.SportType
+	}
+	return a.Type
+}
+
+// workoutType returns the published workout type, or "" for default
+// activities
+func (a stravaActivity) workoutType() string {
+	if a.WorkoutType == nil {
+		return ""
+	}
+	return stravaWorkoutTypes[*a.WorkoutType]
+}
+
+// findRaces returns all activities marked as races, newest first
+func findRaces(activities []stravaActivity) []models.StravaActivity {
+	races := make([]models.StravaActivity, 0)
+	for _, activity := range activities {
+		if activity.workoutType() == workoutTypeRace {
+			races = append(races, convertActivity(activity))
+		}
+	}
+	sort.Slice(races, func(i, j int) bool {
+		return races[i].StartDate.After(races[j].StartDate)
+	})
+	return races
+}
@@ -0,0 +1,71 @@
+package scrapers
+
+import (
+	"testing"
+)
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestStravaActivity_SportType(t *testing.T) {
+	gravel := stravaActivity{Type: "Ride", SportType: "GravelRide"}
+	if got := gravel.sportType(); got != "GravelRide" {
+		t.Errorf("Expected the sport type, got %q", got)
+	}
+	archived := stravaActivity{Type: "Run"}
+	if got := archived.sportType(); got != "Run" {
+		t.Errorf("Expected the legacy type without sport type, got %q", got)
+	}
+
+	// Classification uses the sport type, not the legacy type
+	scraper := NewStravaScraper("id", "secret", "refresh", newMockCache())
+	if err := scraper.SetDisciplines([]Discipline{
+		{ID: "cycling", Types: []string{"Ride"}},
+		{ID: "gravel", Types: []string{"GravelRide"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	disciplines := scraper.buildDisciplines([]stravaActivity{gravel})
+	if len(disciplines) != 1 || disciplines[0].Type != "gravel" || disciplines[0].Activities[0].Type != "GravelRide" {
+		t.Errorf("Expected the gravel ride in the gravel discipline, got %+v", disciplines)
+	}
+}
+
+func TestStravaActivity_WorkoutType(t *testing.T) {
+	tests := []struct {
+		code     *int
+		expected string
+	}{
+		{nil, ""},
+		{intPtr(0), ""},
+		{intPtr(1), workoutTypeRace},
+		{intPtr(2), workoutTypeLongRun},
+		{intPtr(3), workoutTypeWorkout},
+		{intPtr(10), ""},
+		{intPtr(11), workoutTypeRace},
+		{intPtr(12), workoutTypeWorkout},
+	}
+	for _, tt := range tests {
+		if got := (stravaActivity{WorkoutType: tt.code}).workoutType(); got != tt.expected {
+			t.Errorf("workoutType(%v) = %q, expected %q", tt.code, got, tt.expected)
+		}
+	}
+}
+
+func TestFindRaces(t *testing.T) {
+	marathon := testStravaActivity(1, "City Marathon")
+	marathon.WorkoutType = intPtr(1)
+	longRun := testStravaActivity(2, "Long run")
+	longRun.WorkoutType = intPtr(2)
+	crit := testStravaActivity(3, "Criterium")
+	crit.Type, crit.SportType, crit.WorkoutType = "Ride", "Ride", intPtr(11)
+
+	races := findRaces([]stravaActivity{marathon, longRun, crit})
+	if len(races) != 2 || races[0].ID != 3 || races[1].ID != 1 {
+		t.Fatalf("Expected both races newest first, got %+v", races)
+	}
+	if !races[0].Race || races[0].WorkoutType != workoutTypeRace {
+		t.Errorf("Expected the race flag, got %+v", races[0])
+	}
+}
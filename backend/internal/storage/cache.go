@@ -9,7 +9,8 @@ import (
 	"time"
 )
 
-// Cache defines the interface for caching data
+// Cache defines the interface for caching data. Implementations must be
+// safe for concurrent use; scrapers fetch from several goroutines.
 type Cache interface {
 	// Get retrieves data from cache. Returns nil if not found or expired.
 	Get(key string) ([]byte, error)
@@ -96,9 +97,28 @@ func (c *FileCache) Set(key string, data []byte, ttl time.Duration) error {
 		return fmt.Errorf("failed to marshal cache entry: %w", err)
 	}
 
-	// Write to file
+	// Write to a temporary file and rename it into place, so concurrent
+	// readers never see a partially written entry
 	filePath := c.getFilePath(key)
-	if err := os.WriteFile(filePath, entryData, 0644); err != nil {
+	tmp, err := os.CreateTemp(c.baseDir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	if _, err := tmp.Write(entryData); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		_ = os.Remove(tmp.Name())
 		return fmt.Errorf("failed to write cache file: %w", err)
 	}
 
@@ -19,6 +19,9 @@ const (
 	generatedDataDir       = "./data/generated"
 	defaultRefreshInterval = 4 * time.Hour
 	githubRawBaseURL       = "https://raw.githubusercontent.com/MrCodeEU/homepage/refs/heads/main/backend/data/generated"
+
+	// assetsDirName is the directory of mirrored images inside the data directory
+	assetsDirName = "assets"
 )
 
 // DataLoader loads pre-generated data files and supports auto-refresh from GitHub
@@ -26,6 +29,7 @@ type DataLoader struct {
 	dataDir         string
 	refreshInterval time.Duration
 	httpClient      *http.Client
+	baseURL         string       // where refreshed files are downloaded from
 	mu              sync.RWMutex // Protects file access during refresh
 }
 
@@ -40,6 +44,7 @@ func NewDataLoader(dataDir string) *DataLoader {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		baseURL: githubRawBaseURL,
 	}
 }
 
@@ -89,7 +94,7 @@ func (d *DataLoader) StartAutoRefresh(ctx context.Context) {
 
 // refreshFromGitHub fetches the latest data files from the GitHub repository
 func (d *DataLoader) refreshFromGitHub() {
-	files := []string{"github.json", "linkedin.json", "strava.json"}
+	files := []string{"github.json", "github_groups.json", "github_activity.json", "linkedin.json", "strava.json"}
 	successCount := 0
 
 	for _, file := range files {
@@ -101,34 +106,104 @@ func (d *DataLoader) refreshFromGitHub() {
 	}
 
 	log.Printf("Data refresh complete: %d/%d files updated", successCount, len(files))
+
+	d.refreshAssets()
 }
 
-// fetchAndSaveFile downloads a single file from GitHub and saves it locally
-func (d *DataLoader) fetchAndSaveFile(filename string) error {
-	url := fmt.Sprintf("%s/%s", githubRawBaseURL, filename)
+// refreshAssets downloads the mirrored images and their resized variants
+// referenced by github.json that are missing locally. Asset files are
+// content-addressed, so existing files never need to be updated.
+func (d *DataLoader) refreshAssets() {
+	d.mu.RLock()
+	var wrapped struct {
+		Data []struct {
+			ImageAssets []models.ImageAsset `json:"image_assets"`
+		} `json:"data"`
+	}
+	err := d.loadJSON("github.json", &wrapped)
+	d.mu.RUnlock()
+	if err != nil {
+		log.Printf("⚠ Failed to read image assets: %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(d.AssetsDir(), 0755); err != nil {
+		log.Printf("⚠ Failed to create asset directory: %v", err)
+		return
+	}
+
+	names := make([]string, 0)
+	for _, project := range wrapped.Data {
+		for _, asset := range project.ImageAssets {
+			names = append(names, filepath.Base(asset.URL))
+			for _, variant := range asset.Variants {
+				names = append(names, filepath.Base(variant.URL))
+			}
+		}
+	}
 
+	fetched := 0
+	for _, name := range names {
+		target := filepath.Join(d.AssetsDir(), name)
+		if _, err := os.Stat(target); err == nil {
+			continue
+		}
+
+		data, err := d.fetch(fmt.Sprintf("%s/%s/%s", d.baseURL, assetsDirName, name), "*/*")
+		if err != nil {
+			log.Printf("⚠ Failed to refresh asset %s: %v", name, err)
+			continue
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			log.Printf("⚠ Failed to write asset %s: %v", name, err)
+			continue
+		}
+		fetched++
+	}
+
+	if fetched > 0 {
+		log.Printf("✓ Downloaded %d image assets", fetched)
+	}
+}
+
+// AssetsDir returns the directory holding mirrored project images
+func (d *DataLoader) AssetsDir() string {
+	return filepath.Join(d.dataDir, assetsDirName)
+}
+
+// fetch downloads a file from GitHub
+func (d *DataLoader) fetch(url, accept string) ([]byte, error) {
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Add headers to avoid caching issues
 	req.Header.Set("Cache-Control", "no-cache")
-	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept", accept)
 
 	resp, err := d.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to fetch: %w", err)
+		return nil, fmt.Errorf("failed to fetch: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return data, nil
+}
+
+// fetchAndSaveFile downloads a single file from GitHub and saves it locally
+func (d *DataLoader) fetchAndSaveFile(filename string) error {
+	data, err := d.fetch(fmt.Sprintf("%s/%s", d.baseURL, filename), "application/json")
+	if err != nil {
+		return err
 	}
 
 	// Validate JSON before saving
@@ -162,6 +237,30 @@ func (d *DataLoader) LoadGitHub() (interface{}, error) {
 	return wrapped.Data, nil
 }
 
+// LoadGitHubGroups loads GitHub projects grouped by category
+func (d *DataLoader) LoadGitHubGroups() (interface{}, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var wrapped models.GeneratedData
+	if err := d.loadJSON("github_groups.json", &wrapped); err != nil {
+		return nil, err
+	}
+	return wrapped.Data, nil
+}
+
+// LoadGitHubActivity loads the GitHub contribution calendar and recent events
+func (d *DataLoader) LoadGitHubActivity() (interface{}, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var wrapped models.GeneratedData
+	if err := d.loadJSON("github_activity.json", &wrapped); err != nil {
+		return nil, err
+	}
+	return wrapped.Data, nil
+}
+
 // LoadStrava loads Strava data
 func (d *DataLoader) LoadStrava() (*models.StravaData, error) {
 	d.mu.RLock()
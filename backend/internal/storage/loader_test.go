@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDataLoader_RefreshAssets(t *testing.T) {
+	files := map[string]string{
+		"/assets/abc.png":      "original",
+		"/assets/abc-320w.jpg": "small",
+		"/assets/abc-640w.jpg": "medium",
+		"/assets/existing.png": "unexpected",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	dataDir := t.TempDir()
+	github := `{"data": [{"image_assets": [
+		{"url": "/assets/abc.png", "variants": [{"url": "/assets/abc-320w.jpg", "width": 320}, {"url": "/assets/abc-640w.jpg", "width": 640}]},
+		{"url": "/assets/existing.png"}
+	]}]}`
+	if err := os.WriteFile(filepath.Join(dataDir, "github.json"), []byte(github), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dataDir, assetsDirName), 0755); err != nil {
+		t.Fatal(err)
+	}
+	existing := filepath.Join(dataDir, assetsDirName, "existing.png")
+	if err := os.WriteFile(existing, []byte("kept"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewDataLoader(dataDir)
+	loader.baseURL = server.URL
+	loader.refreshAssets()
+
+	for _, name := range []string{"abc.png", "abc-320w.jpg", "abc-640w.jpg"} {
+		content, err := os.ReadFile(filepath.Join(loader.AssetsDir(), name))
+		if err != nil {
+			t.Errorf("Expected %s to be downloaded: %v", name, err)
+			continue
+		}
+		if string(content) != files["/assets/"+name] {
+			t.Errorf("Unexpected content of %s: %q", name, content)
+		}
+	}
+
+	// Content-addressed files are never downloaded again
+	if content, err := os.ReadFile(existing); err != nil || string(content) != "kept" {
+		t.Errorf("Expected the existing asset to be kept, got %q (err: %v)", content, err)
+	}
+}
@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TokenStore persists a credential that changes over time, such as an
+// OAuth refresh token that is rotated by the provider
+type TokenStore interface {
+	// Load returns the stored token and when it was saved. Returns an
+	// empty token if none has been stored yet.
+	Load() (token string, updatedAt time.Time, err error)
+
+	// Save replaces the stored token
+	Save(token string) error
+}
+
+// tokenFile is the on-disk format of FileTokenStore. Exactly one of Token
+// and Encrypted is set.
+type tokenFile struct {
+	Token     string    `json:"token,omitempty"`
+	Encrypted string    `json:"encrypted,omitempty"` // base64 of nonce + AES-GCM ciphertext
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FileTokenStore implements TokenStore with a single file that only the
+// owner can read. With a passphrase the token is encrypted with AES-256-GCM,
+// so it can live in a shared cache such as the GitHub Actions cache.
+type FileTokenStore struct {
+	path string
+	key  []byte // nil stores the token in plain text
+}
+
+// NewFileTokenStore creates a token store at path. An empty passphrase
+// stores the token unencrypted.
+func NewFileTokenStore(path, passphrase string) *FileTokenStore {
+	store := &FileTokenStore{path: path}
+	if passphrase != "" {
+		sum := sha256.Sum256([]byte(passphrase))
+		store.key = sum[:]
+	}
+	return store
+}
+
+// Path returns the file the token is stored in
+func (s *FileTokenStore) Path() string {
+	return s.path
+}
+
+// Load reads the stored token
+func (s *FileTokenStore) Load() (string, time.Time, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", time.Time{}, nil
+		}
+		return "", time.Time{}, fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	var file tokenFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to parse token file %s: %w", s.path, err)
+	}
+
+	// Plain tokens are still read after a passphrase is configured; the
+	// next Save encrypts them
+	if file.Encrypted == "" {
+		return file.Token, file.UpdatedAt, nil
+	}
+	if s.key == nil {
+		return "", time.Time{}, fmt.Errorf("token file %s is encrypted but no passphrase is set", s.path)
+	}
+	token, err := s.decrypt(file.Encrypted)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, file.UpdatedAt, nil
+}
+
+// Save writes the token, replacing the file atomically
+func (s *FileTokenStore) Save(token string) error {
+	file := tokenFile{UpdatedAt: time.Now().UTC()}
+	if s.key != nil {
+		encrypted, err := s.encrypt(token)
+		if err != nil {
+			return err
+		}
+		file.Encrypted = encrypted
+	} else {
+		file.Token = token
+	}
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token file: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create token directory: %w", err)
+	}
+	// CreateTemp creates the file with mode 0600
+	tmp, err := os.CreateTemp(dir, ".token-*")
+	if err != nil {
+		return fmt.Errorf("failed to create token file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	return nil
+}
+
+// encrypt seals token with AES-GCM and returns nonce and ciphertext as base64
+func (s *FileTokenStore) encrypt(token string) (string, error) {
+	gcm, err := s.cipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(token), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt reverses encrypt
+func (s *FileTokenStore) decrypt(encoded string) (string, error) {
+	gcm, err := s.cipher()
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode token file: %w", err)
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("token file is truncated")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", errors.New("failed to decrypt token file (wrong passphrase?)")
+	}
+	return string(plain), nil
+}
+
+// cipher returns the AES-GCM cipher for the store's key
+func (s *FileTokenStore) cipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return gcm, nil
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileTokenStore_Missing(t *testing.T) {
+	store := NewFileTokenStore(filepath.Join(t.TempDir(), "token.json"), "")
+
+	token, updatedAt, err := store.Load()
+	if err != nil || token != "" || !updatedAt.IsZero() {
+		t.Errorf("Expected an empty token without error, got %q %v %v", token, updatedAt, err)
+	}
+}
+
+func TestFileTokenStore_Plain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "token.json")
+	store := NewFileTokenStore(path, "")
+
+	if err := store.Save("refresh-1"); err != nil {
+		t.Fatalf("Failed to save token: %v", err)
+	}
+	if err := store.Save("refresh-2"); err != nil {
+		t.Fatalf("Failed to replace token: %v", err)
+	}
+
+	token, updatedAt, err := store.Load()
+	if err != nil {
+		t.Fatalf("Failed to load token: %v", err)
+	}
+	if token != "refresh-2" || updatedAt.IsZero() {
+		t.Errorf("Expected the latest token with a timestamp, got %q %v", token, updatedAt)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat token file: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("Expected the token file to be private (0600), got %v", perm)
+	}
+}
+
+func TestFileTokenStore_Encrypted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.json")
+	store := NewFileTokenStore(path, "correct horse")
+
+	if err := store.Save("secret-refresh-token"); err != nil {
+		t.Fatalf("Failed to save token: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read token file: %v", err)
+	}
+	if strings.Contains(string(data), "secret-refresh-token") {
+		t.Error("Expected the token to be encrypted on disk")
+	}
+
+	token, _, err := store.Load()
+	if err != nil || token != "secret-refresh-token" {
+		t.Errorf("Expected the decrypted token, got %q %v", token, err)
+	}
+
+	if _, _, err := NewFileTokenStore(path, "wrong").Load(); err == nil {
+		t.Error("Expected a wrong passphrase to fail")
+	}
+	if _, _, err := NewFileTokenStore(path, "").Load(); err == nil {
+		t.Error("Expected an encrypted file without passphrase to fail")
+	}
+}
+
+func TestFileTokenStore_PlainWithPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.json")
+	if err := NewFileTokenStore(path, "").Save("plain"); err != nil {
+		t.Fatalf("Failed to save token: %v", err)
+	}
+
+	// Adding a passphrase keeps the existing token readable until it is saved again
+	store := NewFileTokenStore(path, "passphrase")
+	if token, _, err := store.Load(); err != nil || token != "plain" {
+		t.Errorf("Expected the plain token, got %q %v", token, err)
+	}
+	if err := store.Save("rotated"); err != nil {
+		t.Fatalf("Failed to save token: %v", err)
+	}
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "rotated") {
+		t.Error("Expected the next save to encrypt the token")
+	}
+}
@@ -71,18 +71,111 @@ export interface ProjectLink {
 	icon?: string; // Optional Material Design icon name (e.g., "mdi:rocket-launch")
 }
 
+export interface ProjectRelease {
+	tag: string;
+	name?: string;
+	url: string;
+	published_at: string;
+}
+
+export interface LanguageShare {
+	name: string;
+	bytes: number;
+	percentage: number; // 0-100
+}
+
+export interface ImageAsset {
+	url: string;    // local URL, e.g. /assets/3f2a9c....png
+	source: string; // original remote URL
+	width?: number;
+	height?: number;
+	mime: string;
+	hash: string;   // SHA-256 of the file content
+	size: number;   // bytes
+	variants?: ImageVariant[]; // resized copies for srcset, smallest first
+	placeholder?: string;      // tiny data URI, shown blurred while loading
+	dominant_color?: string;   // #rrggbb
+}
+
+export interface ImageVariant {
+	url: string;
+	width: number;
+	height: number;
+	size: number; // bytes
+}
+
 export interface Project {
 	name: string;
+	owner: string;
 	description: string;
 	url: string;
+	homepage?: string;
 	stars: number;
+	forks: number;
+	open_issues: number; // issues and pull requests
+	license?: string;    // SPDX identifier
+	pushed_at: string;
+	latest_release?: ProjectRelease;
 	language: string;
+	languages: LanguageShare[]; // largest first
 	topics: string[];
 	images: string[];
+	image_assets?: ImageAsset[]; // mirrored copies of images
 	badges: string[];
 	featured: boolean;
 	links: ProjectLink[];
 	priority?: number;
+	display_name?: string; // shown instead of name when set
+	category?: string;
+	order_group: number;   // lower groups are listed first
+	start_date?: string;   // YYYY, YYYY-MM or YYYY-MM-DD
+	end_date?: string;     // unset while ongoing
+	summary?: string;      // first paragraph of the README, plain text
+	highlights?: string[]; // items of the README's features list
+	excerpt_html?: string; // sanitized HTML of summary and highlights
+}
+
+export interface ProjectGroup {
+	category: string; // "Other" collects uncategorized projects
+	projects: Project[];
+}
+
+// GitHub Activity
+export interface GitHubActivity {
+	username?: string;
+	from?: string; // YYYY-MM-DD, first day of the calendar window
+	to?: string;   // YYYY-MM-DD
+	calendar?: ContributionCalendar; // only generated with a GitHub token
+	events: ActivityEvent[];
+	repo_commits: RepoCommitCount[]; // most commits first
+}
+
+export interface ContributionCalendar {
+	total: number;
+	days: ContributionDay[]; // oldest first
+}
+
+export interface ContributionDay {
+	date: string;  // YYYY-MM-DD
+	count: number;
+	level: number; // 0 (none) to 4 (busiest quartile)
+}
+
+export interface ActivityEvent {
+	type: 'push' | 'pull_request' | 'release';
+	repo: string; // owner/name
+	title: string;
+	url: string;
+	ref?: string;    // branch of a push, tag of a release
+	action?: 'opened' | 'merged' | 'closed';
+	commits?: number;
+	created_at: string;
+}
+
+export interface RepoCommitCount {
+	repo: string;
+	url: string;
+	commits: number;
 }
 
 // Strava Data
@@ -92,7 +185,65 @@ export interface StravaData {
 	recent_activities: StravaActivity[];
 	best_activities: StravaBestRecords;
 	personal_records: StravaRecord[];
+	races?: StravaActivity[]; // newest first
 	disciplines: StravaDiscipline[];
+	trends?: StravaTrends;
+	maps?: StravaMaps;
+	gear?: StravaGear[]; // active first, then by distance
+}
+
+export interface StravaGear {
+	id: string;
+	name: string;
+	kind: 'shoes' | 'bike';
+	brand?: string;
+	model?: string;
+	primary: boolean;
+	retired: boolean;
+	distance: number;          // meters, as tracked by Strava
+	activity_count: number;    // published activities with this gear
+	activity_distance: number; // meters, over those activities
+	moving_time: number;       // seconds, over those activities
+	last_used?: string;
+}
+
+// Routes and heatmap; points inside privacy zones are removed
+export interface StravaMaps {
+	routes: {
+		type: 'FeatureCollection';
+		features: StravaRouteFeature[]; // most recent activities
+	};
+	heatmap: {
+		cell_size: number; // degrees
+		cells: { lat: number; lng: number; count: number }[];
+	};
+}
+
+export interface StravaRouteFeature {
+	type: 'Feature';
+	geometry:
+		| { type: 'LineString'; coordinates: [number, number][] }      // [lng, lat]
+		| { type: 'MultiLineString'; coordinates: [number, number][][] };
+	properties: {
+		id: number;
+		name: string;
+		type: string;
+		distance: number;
+		start_date: string;
+		start?: [number, number]; // [lng, lat]; omitted inside privacy zones
+	};
+}
+
+export interface StravaTrends {
+	timezone: string;         // IANA zone in which periods start
+	weekly: StravaPeriod[];   // oldest first, weeks start on Monday
+	monthly: StravaPeriod[];
+}
+
+export interface StravaPeriod {
+	start: string;                            // first day, "YYYY-MM-DD"
+	total: StravaStats;
+	disciplines: Record<string, StravaStats>; // absent if no activities
 }
 
 export interface StravaStats {
@@ -110,7 +261,7 @@ export interface StravaActivity {
 	moving_time: number;
 	elapsed_time: number;
 	total_elevation_gain: number;
-	type: string;
+	type: string; // Strava sport type, e.g. "TrailRun"
 	start_date: string;
 	average_pace: number;
 	average_speed: number;
@@ -118,16 +269,32 @@ export interface StravaActivity {
 	average_heartrate?: number;
 	max_heartrate?: number;
 	calories?: number;
+	average_watts?: number;
+	max_watts?: number;
+	workout_type?: 'race' | 'long_run' | 'workout';
+	race?: boolean;
+	device_name?: string;
+	gear_id?: string;
 }
 
 export interface StravaDiscipline {
-	type: string;           // "cycling" | "training"
-	label: string;          // "Cycling & Spinning" | "Training"
+	type: string;           // configured discipline ID, e.g. "cycling" | "training"
+	label: string;          // e.g. "Cycling & Spinning" | "Training"
 	count: number;
 	total_time: number;     // seconds
 	total_distance: number; // meters
+	has_distance?: boolean; // whether distance matters for the discipline
 	avg_heartrate: number;  // 0 if unavailable
 	activities: StravaActivity[];
+	bests?: StravaBest[];
+}
+
+export interface StravaBest {
+	type: string;  // "longest_distance" | "most_elevation" | "fastest_20k" | "max_power" | ...
+	label: string;
+	value: number; // in unit
+	unit: 'm' | 's' | 'm/s' | 'W';
+	activity: StravaActivity;
 }
 
 export interface StravaBestRecords {
@@ -139,9 +306,11 @@ export interface StravaBestRecords {
 
 export interface StravaRecord {
 	type: string;
+	name?: string;
 	time: number;
 	distance: number;
 	date: string;
+	source?: 'best_effort' | 'activity';
 	activity: StravaActivity;
 }
 
@@ -166,6 +335,18 @@ export async function getProjects(): Promise<Project[]> {
 	return res.json();
 }
 
+export async function getProjectGroups(): Promise<ProjectGroup[]> {
+	const res = await fetch(`${API_BASE}/api/projects/groups`);
+	if (!res.ok) throw new Error('Failed to fetch project groups');
+	return res.json();
+}
+
+export async function getGitHubActivity(): Promise<GitHubActivity> {
+	const res = await fetch(`${API_BASE}/api/activity`);
+	if (!res.ok) throw new Error('Failed to fetch GitHub activity');
+	return res.json();
+}
+
 export async function getStravaData(): Promise<StravaData> {
 	const res = await fetch(`${API_BASE}/api/strava`);
 	if (!res.ok) throw new Error('Failed to fetch Strava data');
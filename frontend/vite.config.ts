@@ -13,6 +13,10 @@ export default defineConfig({
 		}
 	},
 	server: {
-		port: 5173
+		port: 5173,
+		proxy: {
+			// Mirrored project images are served by the Go backend in development
+			'/assets': 'http://localhost:8080'
+		}
 	}
 });